@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stebennett/bin-notifier/pkg/cache"
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/scraper"
+)
+
+// Daemon runs the notifier each time its cron schedule fires until the context
+// is cancelled. A run in progress is allowed to finish before Serve returns.
+type Daemon struct {
+	Notifier *Notifier
+	Schedule cron.Schedule
+	// After waits for the given duration; it defaults to time.After.
+	After func(time.Duration) <-chan time.Time
+}
+
+// NewDaemon creates a Daemon for n using the schedule in cfg.Daemon.
+func NewDaemon(n *Notifier, cfg config.Config) (*Daemon, error) {
+	schedule, err := cron.ParseStandard(cfg.Daemon.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	return &Daemon{Notifier: n, Schedule: schedule}, nil
+}
+
+// Serve waits for each scheduled time in the configured time zone and runs the
+// notifier, logging any errors rather than stopping.
+func (d *Daemon) Serve(ctx context.Context, cfg config.Config) {
+	after := d.After
+	if after == nil {
+		after = time.After
+	}
+	zone := cfg.Zone
+	if zone == nil {
+		zone = time.UTC
+	}
+
+	for {
+		now := d.Notifier.Clock().In(zone)
+		next := d.Schedule.Next(now)
+		log.Printf("Next run at %s", next.Format(time.RFC1123))
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Shutting down")
+			return
+		case <-after(next.Sub(now)):
+		}
+
+		// A run in progress is not cancelled on shutdown, so it can finish.
+		logResults(d.Notifier.RunContext(context.WithoutCancel(ctx), cfg))
+	}
+}
+
+// cachingFactory wraps factory so scraped collections are reused from c until
+// they expire, saving a browser session on every run.
+func cachingFactory(factory ScraperFactory, c *cache.ScraperCache) ScraperFactory {
+	return func(name string) (BinScraper, error) {
+		s, err := factory(name)
+		if err != nil {
+			return nil, err
+		}
+		return &cachedScraper{name: name, scraper: s, cache: c}, nil
+	}
+}
+
+// cachedScraper is a BinScraper that consults a ScraperCache first.
+type cachedScraper struct {
+	name    string
+	scraper BinScraper
+	cache   *cache.ScraperCache
+}
+
+func (s *cachedScraper) ScrapeBinTimes(ctx context.Context, postcode string, address string) ([]scraper.BinTime, error) {
+	if binTimes, ok := s.cache.Get(s.name, postcode, address); ok {
+		return binTimes, nil
+	}
+	binTimes, err := s.scraper.ScrapeBinTimes(ctx, postcode, address)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(s.name, postcode, address, binTimes)
+	return binTimes, nil
+}
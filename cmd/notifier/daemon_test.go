@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/cache"
+	"github.com/stebennett/bin-notifier/pkg/clients"
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingScraper counts calls so tests can tell when the cache was used.
+type countingScraper struct {
+	binTimes []scraper.BinTime
+	err      error
+	calls    int
+}
+
+func (s *countingScraper) ScrapeBinTimes(ctx context.Context, postcode string, address string) ([]scraper.BinTime, error) {
+	s.calls++
+	return s.binTimes, s.err
+}
+
+func TestDaemon_RunsAtScheduledTimes(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, london) // Monday
+	tomorrow := time.Date(2026, 6, 16, 0, 0, 0, 0, london)
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{
+			"bracknell": {binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}},
+		}),
+		Channels: []clients.NotificationChannel{mockCh},
+		Clock:    func() time.Time { return now },
+	}
+
+	cfg := createTestConfig()
+	cfg.Zone = london
+	cfg.Daemon.Schedule = "0 18 * * *"
+	daemon, err := NewDaemon(notifier, cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var waits []time.Duration
+	daemon.After = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		if len(waits) == 2 {
+			cancel()
+			return nil
+		}
+		now = now.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- now
+		return ch
+	}
+
+	daemon.Serve(ctx, cfg)
+
+	assert.Equal(t, []time.Duration{6 * time.Hour, 24 * time.Hour}, waits)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, tomorrow, mockCh.calls[0].msg.Date)
+}
+
+func TestDaemon_StopsWhenCancelled(t *testing.T) {
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{}),
+		Clock:          time.Now,
+	}
+	cfg := createTestConfig()
+	cfg.Daemon.Schedule = "*/5 * * * *"
+	daemon, err := NewDaemon(notifier, cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		daemon.Serve(ctx, cfg)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Serve did not return after cancellation")
+	}
+}
+
+func TestNewDaemon_InvalidSchedule(t *testing.T) {
+	cfg := config.Config{Daemon: config.DaemonConfig{Schedule: "every evening"}}
+
+	_, err := NewDaemon(&Notifier{}, cfg)
+
+	assert.Error(t, err)
+}
+
+func TestCachingFactory_ReusesScrapedCollections(t *testing.T) {
+	s := &countingScraper{binTimes: []scraper.BinTime{{Type: "Recycling"}}}
+	factory := cachingFactory(func(string) (BinScraper, error) { return s, nil }, cache.New(time.Hour))
+
+	for range 3 {
+		cached, err := factory("bracknell")
+		require.NoError(t, err)
+		binTimes, err := cached.ScrapeBinTimes(context.Background(), "RG12 1AB", "12345")
+		require.NoError(t, err)
+		assert.Equal(t, s.binTimes, binTimes)
+	}
+	assert.Equal(t, 1, s.calls)
+
+	cached, _ := factory("bracknell")
+	_, err := cached.ScrapeBinTimes(context.Background(), "RG12 1AB", "67890")
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.calls)
+}
+
+func TestCachingFactory_KeysOnScraper(t *testing.T) {
+	scrapers := map[string]*countingScraper{
+		"bracknell": {binTimes: []scraper.BinTime{{Type: "Recycling"}}},
+		"wokingham": {binTimes: []scraper.BinTime{{Type: "Food waste"}}},
+	}
+	factory := cachingFactory(func(name string) (BinScraper, error) { return scrapers[name], nil }, cache.New(time.Hour))
+
+	for _, name := range []string{"bracknell", "wokingham", "bracknell"} {
+		cached, err := factory(name)
+		require.NoError(t, err)
+		binTimes, err := cached.ScrapeBinTimes(context.Background(), "RG12 1AB", "12345")
+		require.NoError(t, err)
+		assert.Equal(t, scrapers[name].binTimes, binTimes)
+	}
+	assert.Equal(t, 1, scrapers["bracknell"].calls)
+	assert.Equal(t, 1, scrapers["wokingham"].calls)
+}
+
+func TestCachingFactory_DoesNotCacheErrors(t *testing.T) {
+	s := &countingScraper{err: errors.New("timeout")}
+	factory := cachingFactory(func(string) (BinScraper, error) { return s, nil }, cache.New(time.Hour))
+
+	for range 2 {
+		cached, err := factory("bracknell")
+		require.NoError(t, err)
+		_, err = cached.ScrapeBinTimes(context.Background(), "RG12 1AB", "12345")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, s.calls)
+}
+
+func TestCachingFactory_PassesThroughFactoryErrors(t *testing.T) {
+	factory := cachingFactory(newMockFactory(map[string]*mockScraper{}), cache.New(time.Hour))
+
+	_, err := factory("unknown")
+
+	assert.EqualError(t, err, "unknown scraper: unknown")
+}
@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
+	"github.com/stebennett/bin-notifier/pkg/browser"
+	"github.com/stebennett/bin-notifier/pkg/cache"
 	"github.com/stebennett/bin-notifier/pkg/clients"
 	"github.com/stebennett/bin-notifier/pkg/config"
 	"github.com/stebennett/bin-notifier/pkg/dateutil"
+	"github.com/stebennett/bin-notifier/pkg/history"
+	"github.com/stebennett/bin-notifier/pkg/mqtt"
+	"github.com/stebennett/bin-notifier/pkg/retry"
+	"github.com/stebennett/bin-notifier/pkg/schedule"
 	"github.com/stebennett/bin-notifier/pkg/scraper"
+	"github.com/stebennett/bin-notifier/pkg/templates"
 )
 
 // ScraperFactory resolves a BinScraper by name.
@@ -18,51 +32,138 @@ type ScraperFactory func(name string) (BinScraper, error)
 
 // BinScraper is an interface for scraping bin collection times.
 type BinScraper interface {
-	ScrapeBinTimes(postcode string, address string) ([]scraper.BinTime, error)
+	ScrapeBinTimes(ctx context.Context, postcode string, address string) ([]scraper.BinTime, error)
 }
 
-// SMSClient is an interface for sending SMS messages.
-type SMSClient interface {
-	SendSms(from string, to string, body string, dryRun bool) error
+// StatePublisher publishes each location's scraped collections, e.g. to Home Assistant.
+type StatePublisher interface {
+	PublishLocation(loc config.Location, binTimes []scraper.BinTime, today time.Time) error
+}
+
+// HistoryStore remembers delivered notifications so repeat runs can skip them.
+type HistoryStore interface {
+	Sent(n history.Notification) bool
+	Record(n history.Notification, at time.Time) error
 }
 
 // Notifier orchestrates the bin collection notification workflow.
 type Notifier struct {
 	ScraperFactory ScraperFactory
-	SMSClient      SMSClient
+	Channels       []clients.NotificationChannel
+	Publisher      StatePublisher
+	History        HistoryStore
 	Clock          func() time.Time
+	Sleep          func(time.Duration)
+}
+
+// ChannelResult records the outcome of sending one message through one channel.
+// Skipped is set when the message was already delivered by an earlier run, and
+// Attempts counts the sends made including retries.
+type ChannelResult struct {
+	Channel  string
+	Sent     bool
+	Skipped  bool
+	Attempts int
+	Error    error
 }
 
 // NotificationResult contains the result of a notification run for a single location.
 type NotificationResult struct {
 	Label       string
 	Collections []string
-	SMSSent     bool
 	Message     string
+	Channels    []ChannelResult
 	Error       error
+
+	// queued holds the location's messages in digest mode until every location
+	// has been processed.
+	queued []clients.Message
 }
 
-// Run executes the notification workflow for all locations in the config.
+// Sent reports whether any message was delivered for the location.
+func (r NotificationResult) Sent() bool {
+	for _, c := range r.Channels {
+		if c.Sent {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the notification workflow for all locations in the config,
+// sending the reminders that have fallen due by the current Clock time in the
+// configured time zone.
 func (n *Notifier) Run(cfg config.Config) []NotificationResult {
-	now := n.Clock()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return n.RunContext(context.Background(), cfg)
+}
+
+// RunContext is Run with a context that cancels any scrape in progress. Each
+// scrape is also limited to its location's scrape_timeout.
+func (n *Notifier) RunContext(ctx context.Context, cfg config.Config) []NotificationResult {
+	zone := cfg.Zone
+	if zone == nil {
+		zone = time.UTC
+	}
+	now := n.Clock().In(zone)
+	today := dateutil.Today(now, zone)
 	if cfg.TodayDate != "" {
-		parsed, err := time.Parse("2006-01-02", cfg.TodayDate)
+		parsed, err := time.ParseInLocation("2006-01-02", cfg.TodayDate, zone)
 		if err != nil {
 			return []NotificationResult{{Error: fmt.Errorf("invalid today date: %w", err)}}
 		}
 		today = parsed
 	}
+	timeOfDay := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
 
 	results := make([]NotificationResult, 0, len(cfg.Locations))
 	for _, loc := range cfg.Locations {
-		result := n.processLocation(cfg, loc, today)
+		result := n.processLocation(ctx, cfg, loc, today, timeOfDay)
 		results = append(results, result)
 	}
+	if cfg.Digest {
+		n.sendDigests(cfg, results)
+	}
 	return results
 }
 
-func (n *Notifier) processLocation(cfg config.Config, loc config.Location, today time.Time) NotificationResult {
+// defaultReminders is used for locations without reminders: a single put-out
+// reminder, due at any time on the day before the collection.
+var defaultReminders = []config.Reminder{{Offset: -1, Action: config.ActionPutOut}}
+
+// remindersFor returns the location's reminders, including a put_out reminder for
+// each of its notify_days_before.
+func remindersFor(loc config.Location) []config.Reminder {
+	reminders := slices.Clone(loc.Reminders)
+	for _, d := range loc.NotifyDaysBefore {
+		reminders = append(reminders, config.Reminder{Offset: -d, Action: config.ActionPutOut})
+	}
+	if len(reminders) == 0 {
+		return defaultReminders
+	}
+	return reminders
+}
+
+// dueReminders returns the location's reminders for today whose time of day has
+// been reached. Reminders for the same collection and action are only returned
+// once, so overlapping reminders don't send the same message twice in a run.
+func dueReminders(loc config.Location, timeOfDay time.Duration) []config.Reminder {
+	type key struct {
+		offset int
+		action string
+	}
+	seen := make(map[key]bool)
+	var due []config.Reminder
+	for _, r := range remindersFor(loc) {
+		k := key{r.Offset, r.Action}
+		if timeOfDay >= r.TimeOfDay && !seen[k] {
+			seen[k] = true
+			due = append(due, r)
+		}
+	}
+	return due
+}
+
+func (n *Notifier) processLocation(ctx context.Context, cfg config.Config, loc config.Location, today time.Time, timeOfDay time.Duration) NotificationResult {
 	result := NotificationResult{Label: loc.Label}
 
 	log.Printf("[%s] Scraping bin times for %s - %s", loc.Label, loc.AddressCode, loc.PostCode)
@@ -73,78 +174,582 @@ func (n *Notifier) processLocation(cfg config.Config, loc config.Location, today
 		return result
 	}
 
-	binTimes, err := s.ScrapeBinTimes(loc.PostCode, loc.AddressCode)
+	scrapeCtx, cancel := scraper.WithTimeout(ctx, loc.ScrapeTimeout)
+	binTimes, err := s.ScrapeBinTimes(scrapeCtx, loc.PostCode, loc.AddressCode)
+	cancel()
 	if err != nil {
 		result.Error = fmt.Errorf("[%s] scrape error: %w", loc.Label, err)
 		return result
 	}
 
-	tomorrow := today.AddDate(0, 0, 1)
+	if n.Publisher != nil {
+		if cfg.DryRun {
+			log.Printf("[%s] DRY RUN: Would have published collection state to MQTT", loc.Label)
+		} else if err := n.Publisher.PublishLocation(loc, binTimes, today); err != nil {
+			// A publishing failure is reported, but notifications are still sent.
+			log.Printf("[%s] MQTT error: %v", loc.Label, err)
+			recordError(&result, fmt.Errorf("mqtt error: %w", err))
+		}
+	}
 
 	for _, binTime := range binTimes {
 		log.Printf("[%s] Next collection for %s is %s", loc.Label, binTime.Type, binTime.CollectionTime.String())
-		if dateutil.IsDateMatching(binTime.CollectionTime, tomorrow) {
-			result.Collections = append(result.Collections, binTime.Type)
+	}
+
+	due := dueReminders(loc, timeOfDay)
+	if len(due) == 0 {
+		log.Printf("[%s] No reminders due yet", loc.Label)
+		return result
+	}
+	for _, r := range due {
+		// A reminder offset -1 days from the collection is about tomorrow's collection.
+		date := today.AddDate(0, 0, -r.Offset)
+		when := dateutil.RelativeDay(-r.Offset)
+		var err error
+		if r.Action == config.ActionBringIn {
+			err = n.remindBringIn(cfg, loc, binTimes, date, when, &result)
+		} else {
+			err = n.remindPutOut(cfg, loc, binTimes, date, when, &result)
+		}
+		if err != nil {
+			recordError(&result, err)
+			return result
 		}
 	}
 
-	if len(result.Collections) != 0 {
-		result.Message = loc.Label + ": Tomorrows bin collections are: " + strings.Join(result.Collections, ", ")
-		log.Printf("[%s] %s", loc.Label, result.Message)
+	if result.Message == "" {
+		log.Printf("[%s] No collections due for today's reminders", loc.Label)
+	}
+	return result
+}
+
+// remindPutOut sends the collections scraped for date, or a warning for each
+// expected collection on date that the council website doesn't show. Send
+// failures are recorded on the result; the returned error stops the location.
+func (n *Notifier) remindPutOut(cfg config.Config, loc config.Location, binTimes []scraper.BinTime, date time.Time, when string, result *NotificationResult) error {
+	types := scrapedOn(binTimes, date)
+	if len(types) != 0 {
+		result.Collections = append(result.Collections, types...)
+		msg := clients.Message{
+			Kind:     clients.KindCollection,
+			Title:    loc.Label + ": bin collection " + when,
+			Location: loc.Label,
+			PostCode: loc.PostCode,
+			Date:     date,
+			When:     when,
+			Types:    types,
+			Source:   templates.SourceScraped,
+		}
+		return n.notify(cfg, loc, msg, result)
+	}
 
-		err = n.SMSClient.SendSms(cfg.FromNumber, cfg.ToNumber, result.Message, cfg.DryRun)
+	expected, err := expectedOn(cfg, loc, date)
+	if err != nil {
+		return err
+	}
+	for _, cd := range expected {
+		warning := clients.Message{
+			Kind:     clients.KindScheduleWarning,
+			Title:    loc.Label + ": expected collection not scheduled",
+			Location: loc.Label,
+			PostCode: loc.PostCode,
+			Date:     date,
+			When:     when,
+			Types:    cd.Types,
+			Source:   templates.SourceExpected,
+		}
+		if err := n.notify(cfg, loc, warning, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remindBringIn sends a reminder to bring the bins in after the collections on
+// date. The council website may already show the next collection by then, so
+// the configured schedule is used when nothing was scraped for date.
+func (n *Notifier) remindBringIn(cfg config.Config, loc config.Location, binTimes []scraper.BinTime, date time.Time, when string, result *NotificationResult) error {
+	msg := clients.Message{
+		Kind:     clients.KindBringIn,
+		Title:    loc.Label + ": bring your bins in",
+		Location: loc.Label,
+		PostCode: loc.PostCode,
+		Date:     date,
+		When:     when,
+		Types:    scrapedOn(binTimes, date),
+		Source:   templates.SourceScraped,
+	}
+	if len(msg.Types) == 0 {
+		expected, err := expectedOn(cfg, loc, date)
 		if err != nil {
-			result.Error = fmt.Errorf("[%s] SMS error: %w", loc.Label, err)
-			return result
+			return err
 		}
-		result.SMSSent = true
+		for _, cd := range expected {
+			msg.Types = append(msg.Types, cd.Types...)
+		}
+		msg.Source = templates.SourceExpected
+	}
+	if len(msg.Types) == 0 {
+		return nil
+	}
+	return n.notify(cfg, loc, msg, result)
+}
+
+// expectedOn returns the location's configured collections on date, warning
+// when its holiday shift rule needs bank holidays the calendar doesn't list.
+func expectedOn(cfg config.Config, loc config.Location, date time.Time) ([]config.CollectionDay, error) {
+	if loc.HolidayShift.Days > 0 && !cfg.HolidayCalendar.Covers(date) {
+		log.Printf("[%s] WARNING: the %s holiday calendar has no bank holidays for %d; collections won't be shifted. Add them under holidays.dates", loc.Label, cfg.Holidays.Calendar, date.Year())
+	}
+	return schedule.CollectionsOn(loc, date, cfg.HolidayCalendar)
+}
+
+// notify renders msg with the location's template, adds it to the result and
+// dispatches it. Only a template error is returned; send failures are recorded
+// on the result so the location's other messages still go out.
+func (n *Notifier) notify(cfg config.Config, loc config.Location, msg clients.Message, result *NotificationResult) error {
+	body, err := renderMessage(cfg, loc, "", msg)
+	if err != nil {
+		return err
+	}
+	log.Printf("[%s] %s", loc.Label, body)
+	if result.Message != "" {
+		result.Message += "; " + body
 	} else {
-		for _, cd := range loc.CollectionDays {
-			if tomorrow.Weekday() != cd.Day {
-				continue
+		result.Message = body
+	}
+
+	msg.Body = body
+	if err := n.dispatch(cfg, loc, msg, result); err != nil {
+		recordError(result, err)
+	}
+	return nil
+}
+
+// scrapedOn returns the scraped bin types collected on date.
+func scrapedOn(binTimes []scraper.BinTime, date time.Time) []string {
+	var types []string
+	for _, binTime := range binTimes {
+		if dateutil.IsDateMatching(binTime.CollectionTime, date) {
+			types = append(types, binTime.Type)
+		}
+	}
+	return types
+}
+
+// dispatch sends msg straight away, or holds it on the result when the run is
+// sending a digest.
+func (n *Notifier) dispatch(cfg config.Config, loc config.Location, msg clients.Message, result *NotificationResult) error {
+	if cfg.Digest {
+		result.queued = append(result.queued, msg)
+		return nil
+	}
+	return n.send(cfg, loc, msg, result)
+}
+
+// digestKey identifies a digest on a channel: its recipient, where an empty
+// recipient stands for the channel's own configured addresses, and the route of
+// channels whose delivery depends on the location.
+type digestKey struct {
+	recipient string
+	route     string
+}
+
+// digestGroup is the set of queued messages bound for one digest. sources holds
+// the index of the result each message came from.
+type digestGroup struct {
+	messages []clients.Message
+	sources  []int
+}
+
+// sendDigests combines the messages queued by every location into a single
+// message per recipient on each channel. Messages the channel routes by location
+// are only combined with others on the same route, so a location's own chats or
+// colour still apply. Each outcome is recorded on the results of the locations
+// that contributed to the digest.
+func (n *Notifier) sendDigests(cfg config.Config, results []NotificationResult) {
+	for _, ch := range n.Channels {
+		router, _ := ch.(clients.Router)
+		groups := make(map[digestKey]*digestGroup)
+		var order []digestKey
+		skipped := make(map[int]bool)
+		add := func(recipient string, msg clients.Message, idx int) {
+			if n.alreadySent(cfg, msg, ch.Name(), recipient) {
+				log.Printf("[%s] Already sent %s message via %s, skipping", msg.Location, msg.Kind, ch.Name())
+				skipped[idx] = true
+				return
 			}
-			if cd.EveryNWeeks > 1 {
-				refDate, err := time.Parse("2006-01-02", cd.ReferenceDate)
+			key := digestKey{recipient: recipient}
+			if router != nil {
+				routed := msg
+				if recipient != "" {
+					routed.To = []string{recipient}
+				}
+				key.route = router.Route(routed)
+			}
+			g, ok := groups[key]
+			if !ok {
+				g = &digestGroup{}
+				groups[key] = g
+				order = append(order, key)
+			}
+			g.messages = append(g.messages, msg)
+			g.sources = append(g.sources, idx)
+		}
+
+		for i := range results {
+			loc := cfg.Locations[i]
+			for _, msg := range results[i].queued {
+				body, err := renderMessage(cfg, loc, ch.Name(), msg)
 				if err != nil {
-					result.Error = fmt.Errorf("[%s] invalid reference_date in collection schedule: %w", loc.Label, err)
-					return result
+					recordError(&results[i], fmt.Errorf("%s error: %w", ch.Name(), err))
+					continue
 				}
-				if !dateutil.IsOnWeek(refDate, tomorrow, cd.EveryNWeeks) {
+				msg.Body = body
+
+				to, routed := recipientsFor(cfg, loc, ch.Name(), msg.Types)
+				if !routed {
+					add("", msg, i)
 					continue
 				}
+				for _, addr := range to {
+					add(addr, msg, i)
+				}
 			}
-			msg := fmt.Sprintf("%s: Expected %s collection tomorrow (%s) but none scheduled.",
-				loc.Label, strings.Join(cd.Types, ", "), tomorrow.Weekday())
-			log.Printf("[%s] %s", loc.Label, msg)
-			if result.Message != "" {
-				result.Message += "; " + msg
-			} else {
-				result.Message = msg
+		}
+
+		reported := make(map[int]bool)
+		for _, key := range order {
+			g := groups[key]
+			recipient := key.recipient
+			digest := buildDigest(g.messages)
+			if recipient != "" {
+				digest.To = []string{recipient}
 			}
+			log.Printf("Sending %s digest of %d messages", ch.Name(), len(g.messages))
 
-			err = n.SMSClient.SendSms(cfg.FromNumber, cfg.ToNumber, msg, cfg.DryRun)
+			attempts, err := n.deliver(cfg, ch, digest, "digest")
 			if err != nil {
-				result.Error = fmt.Errorf("[%s] SMS error: %w", loc.Label, err)
-				return result
+				err = fmt.Errorf("%s error: %w", ch.Name(), err)
+			}
+
+			sent := make(map[int]bool)
+			for j, idx := range g.sources {
+				if err == nil {
+					if herr := n.recordSent(cfg, g.messages[j], ch.Name(), []string{recipient}); herr != nil {
+						recordError(&results[idx], herr)
+					}
+				}
+				if sent[idx] {
+					continue
+				}
+				sent[idx] = true
+				reported[idx] = true
+				results[idx].Channels = append(results[idx].Channels, ChannelResult{
+					Channel:  ch.Name(),
+					Sent:     err == nil,
+					Attempts: attempts,
+					Error:    err,
+				})
+				if err != nil {
+					recordError(&results[idx], err)
+				}
 			}
-			result.SMSSent = true
 		}
-		if !result.SMSSent {
-			log.Printf("[%s] No collections tomorrow and not an expected collection day", loc.Label)
+
+		for i := range results {
+			if skipped[i] && !reported[i] {
+				results[i].Channels = append(results[i].Channels, ChannelResult{Channel: ch.Name(), Skipped: true})
+			}
 		}
 	}
+}
 
-	return result
+// buildDigest combines messages into one, one line per message. The digest keeps
+// the location when every message is for the same one, so location-specific
+// channel settings still apply, and likewise the date.
+func buildDigest(messages []clients.Message) clients.Message {
+	digest := clients.Message{
+		Kind:     clients.KindDigest,
+		Title:    "Bin collection reminders",
+		Location: messages[0].Location,
+		PostCode: messages[0].PostCode,
+		Date:     messages[0].Date,
+	}
+	var bodies []string
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		bodies = append(bodies, msg.Body)
+		for _, t := range msg.Types {
+			if !seen[t] {
+				seen[t] = true
+				digest.Types = append(digest.Types, t)
+			}
+		}
+		if msg.Location != messages[0].Location {
+			digest.Location = ""
+			digest.PostCode = ""
+		}
+		if !msg.Date.Equal(messages[0].Date) {
+			digest.Date = time.Time{}
+		}
+	}
+	digest.Body = strings.Join(bodies, "\n")
+	return digest
+}
+
+// recordError adds a location-prefixed error to result, keeping any earlier error.
+func recordError(result *NotificationResult, err error) {
+	err = fmt.Errorf("[%s] %w", result.Label, err)
+	if result.Error != nil {
+		err = errors.Join(result.Error, err)
+	}
+	result.Error = err
+}
+
+// send fans msg out to every channel, recording each outcome on the result.
+// Channels with their own template get msg.Body re-rendered, and channels with
+// recipients configured only deliver to the recipients interested in msg.Types.
+// Recipients that already received msg in an earlier run are skipped unless
+// forced. All channels are attempted; the returned error joins any channel failures.
+func (n *Notifier) send(cfg config.Config, loc config.Location, msg clients.Message, result *NotificationResult) error {
+	var errs []error
+	for _, ch := range n.Channels {
+		chMsg := msg
+		to, routed := recipientsFor(cfg, loc, ch.Name(), msg.Types)
+		if routed && len(to) == 0 {
+			log.Printf("[%s] No %s recipients for %s", loc.Label, ch.Name(), strings.Join(msg.Types, ", "))
+			continue
+		}
+
+		// An empty recipient stands for the channel's own configured addresses.
+		recipients := []string{""}
+		if routed {
+			recipients = to
+		}
+		var pending []string
+		for _, r := range recipients {
+			if !n.alreadySent(cfg, msg, ch.Name(), r) {
+				pending = append(pending, r)
+			}
+		}
+		if len(pending) == 0 {
+			log.Printf("[%s] Already sent %s message via %s, skipping", loc.Label, msg.Kind, ch.Name())
+			result.Channels = append(result.Channels, ChannelResult{Channel: ch.Name(), Skipped: true})
+			continue
+		}
+		if routed {
+			chMsg.To = pending
+		}
+
+		attempts := 0
+		body, err := renderMessage(cfg, loc, ch.Name(), msg)
+		if err == nil {
+			chMsg.Body = body
+			attempts, err = n.deliver(cfg, ch, chMsg, loc.Label)
+		}
+		if herr := n.recordSent(cfg, msg, ch.Name(), delivered(pending, err, attempts)); herr != nil {
+			errs = append(errs, herr)
+		}
+		if err != nil {
+			err = fmt.Errorf("%s error: %w", ch.Name(), err)
+			errs = append(errs, err)
+		}
+		result.Channels = append(result.Channels, ChannelResult{
+			Channel:  ch.Name(),
+			Sent:     err == nil,
+			Attempts: attempts,
+			Error:    err,
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends msg through ch, retrying transient failures with exponential
+// backoff. When the channel reports which recipients failed, only those are
+// retried, including when msg went to the channel's own configured addresses.
+// It returns the number of attempts made.
+func (n *Notifier) deliver(cfg config.Config, ch clients.NotificationChannel, msg clients.Message, label string) (int, error) {
+	sleep := n.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	policy := retry.Policy{
+		MaxAttempts:  cfg.Retry.MaxAttempts,
+		InitialDelay: cfg.Retry.InitialDelay,
+		MaxDelay:     cfg.Retry.MaxDelay,
+	}
+	retryable := func(err error) bool { return !clients.IsPermanent(err) }
+
+	return retry.Do(policy, sleep, retryable, func(attempt int) error {
+		err := ch.Send(msg, cfg.DryRun)
+		if err == nil {
+			return nil
+		}
+		log.Printf("[%s] %s attempt %d failed: %v", label, ch.Name(), attempt, err)
+		if failed := clients.FailedRecipients(err); len(failed) > 0 {
+			msg.To = failed
+		}
+		return err
+	})
+}
+
+// delivered returns the recipients that received a message after a send that
+// ended with err. Only channels that report failed recipients can partly succeed.
+// The empty recipient, standing for the channel's own addresses, only counts as
+// delivered when every one of them was sent to.
+func delivered(recipients []string, err error, attempts int) []string {
+	if attempts == 0 {
+		return nil
+	}
+	if err == nil {
+		return recipients
+	}
+	failed := clients.FailedRecipients(err)
+	if failed == nil {
+		return nil
+	}
+	var ok []string
+	for _, r := range recipients {
+		if r != "" && !slices.Contains(failed, r) {
+			ok = append(ok, r)
+		}
+	}
+	return ok
+}
+
+// alreadySent reports whether an earlier run delivered msg to recipient on the
+// channel. It is always false without a history store or when forced.
+func (n *Notifier) alreadySent(cfg config.Config, msg clients.Message, channel string, recipient string) bool {
+	if n.History == nil || cfg.Force {
+		return false
+	}
+	return n.History.Sent(historyNotification(msg, channel, recipient))
+}
+
+// recordSent records msg as delivered to each recipient on the channel. Nothing
+// is recorded in dry-run mode.
+func (n *Notifier) recordSent(cfg config.Config, msg clients.Message, channel string, recipients []string) error {
+	if n.History == nil || cfg.DryRun {
+		return nil
+	}
+	var errs []error
+	for _, r := range recipients {
+		if err := n.History.Record(historyNotification(msg, channel, r), n.Clock()); err != nil {
+			errs = append(errs, fmt.Errorf("history error: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func historyNotification(msg clients.Message, channel string, recipient string) history.Notification {
+	return history.Notification{
+		Location:  msg.Location,
+		Date:      msg.Date,
+		Kind:      string(msg.Kind),
+		Types:     msg.Types,
+		Channel:   channel,
+		Recipient: recipient,
+	}
+}
+
+// recipientsFor returns the addresses on the named channel that should receive a
+// message about types, from the global and location recipients. routed is false
+// when neither lists the channel, in which case the channel's defaults apply.
+func recipientsFor(cfg config.Config, loc config.Location, channel string, types []string) (to []string, routed bool) {
+	seen := make(map[string]bool)
+	for _, r := range append(append([]config.Recipient{}, cfg.Recipients...), loc.Recipients...) {
+		if r.Channel != channel {
+			continue
+		}
+		routed = true
+		if seen[r.Address] || !wantsTypes(r.Types, types) {
+			continue
+		}
+		seen[r.Address] = true
+		to = append(to, r.Address)
+	}
+	return to, routed
 }
 
-// twilioSMSClientAdapter adapts TwilioClient to the SMSClient interface
-type twilioSMSClientAdapter struct {
-	client *clients.TwilioClient
+// wantsTypes reports whether a recipient filtering on filter wants a message
+// about types. An empty filter matches everything.
+func wantsTypes(filter []string, types []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		for _, t := range types {
+			if strings.EqualFold(f, t) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func (a *twilioSMSClientAdapter) SendSms(from string, to string, body string, dryRun bool) error {
-	_, err := a.client.SendSms(from, to, body, dryRun)
-	return err
+// renderMessage renders the body for msg using the template that applies to the
+// named channel; an empty channel name resolves the location or global template.
+func renderMessage(cfg config.Config, loc config.Location, channel string, msg clients.Message) (string, error) {
+	source := msg.Source
+	if source == "" {
+		source = templates.SourceScraped
+	}
+	return templates.Render(messageTemplate(cfg, loc, channel, msg.Kind), templates.Data{
+		Label:    msg.Location,
+		PostCode: msg.PostCode,
+		Date:     msg.Date,
+		Weekday:  msg.Date.Weekday().String(),
+		When:     msg.When,
+		Types:    msg.Types,
+		Source:   source,
+	})
+}
+
+// messageTemplate returns the template text for a message kind, preferring the
+// channel's override, then the location's, then the global default.
+func messageTemplate(cfg config.Config, loc config.Location, channel string, kind clients.MessageKind) string {
+	var candidates []config.MessageTemplates
+	for _, cc := range cfg.Channels {
+		if channel != "" && cc.Name == channel {
+			candidates = append(candidates, cc.Templates)
+		}
+	}
+	candidates = append(candidates, loc.Templates, cfg.Templates)
+
+	for _, t := range candidates {
+		text := t.Collection
+		switch kind {
+		case clients.KindScheduleWarning:
+			text = t.ScheduleWarning
+		case clients.KindBringIn:
+			text = t.BringIn
+		}
+		if text != "" {
+			return text
+		}
+	}
+	switch kind {
+	case clients.KindScheduleWarning:
+		return templates.DefaultScheduleWarning
+	case clients.KindBringIn:
+		return templates.DefaultBringIn
+	}
+	return templates.DefaultCollection
+}
+
+// printScrapers writes a table of the available council scrapers.
+func printScrapers(w io.Writer, infos []scraper.Info) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCOUNCIL\tPOSTCODES\tFIELDS")
+	for _, info := range infos {
+		var fields []string
+		for _, f := range info.Fields {
+			fields = append(fields, f.Name)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, info.DisplayName, strings.Join(info.PostcodePrefixes, " "), strings.Join(fields, ", "))
+	}
+	tw.Flush()
 }
 
 func main() {
@@ -152,24 +757,91 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if flags.ListScrapers {
+		recipes, err := config.LoadRecipes(flags.ConfigFile)
+		if err == nil {
+			err = scraper.RegisterRecipes(recipes)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		printScrapers(os.Stdout, scraper.List())
+		return
+	}
 
 	cfg, err := config.LoadConfig(flags.ConfigFile)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := scraper.RegisterRecipes(cfg.Recipes); err != nil {
+		log.Fatal(err)
+	}
 
 	cfg.DryRun = flags.DryRun
 	cfg.TodayDate = flags.TodayDate
+	cfg.Force = flags.Force
 
+	channels, err := clients.NewChannels(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Chrome starts on the first scrape and is shared by every location.
+	pool := browser.New(cfg.Browser.MaxTabs)
 	notifier := &Notifier{
 		ScraperFactory: func(name string) (BinScraper, error) {
-			return scraper.NewScraper(name)
+			return scraper.NewScraper(name, scraper.Options{Location: cfg.Zone, Browser: pool})
 		},
-		SMSClient: &twilioSMSClientAdapter{client: clients.NewTwilioClient()},
-		Clock:     time.Now,
+		Channels: channels,
+		Clock:    time.Now,
+	}
+
+	if flags.StateFile != "" {
+		store, err := history.Open(flags.StateFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		notifier.History = store
+	}
+
+	var publisher *mqtt.Publisher
+	if cfg.MQTT.Broker != "" {
+		publisher, err = mqtt.NewPublisher(cfg.MQTT)
+		if err != nil {
+			log.Fatal(err)
+		}
+		notifier.Publisher = publisher
+	}
+
+	if flags.Serve {
+		notifier.ScraperFactory = cachingFactory(notifier.ScraperFactory, cache.New(cfg.Daemon.CacheTTL))
+		daemon, err := NewDaemon(notifier, cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		log.Printf("Running on schedule %q (%s)", cfg.Daemon.Schedule, cfg.Timezone)
+		daemon.Serve(ctx, cfg)
+		pool.Close()
+		if publisher != nil {
+			publisher.Close()
+		}
+		return
 	}
 
 	results := notifier.Run(cfg)
+	pool.Close()
+	if publisher != nil {
+		publisher.Close()
+	}
+	if logResults(results) {
+		os.Exit(1)
+	}
+}
+
+// logResults logs the error of each failed location and reports whether there were any.
+func logResults(results []NotificationResult) bool {
 	hasError := false
 	for _, r := range results {
 		if r.Error != nil {
@@ -177,7 +849,5 @@ func main() {
 			hasError = true
 		}
 	}
-	if hasError {
-		os.Exit(1)
-	}
+	return hasError
 }
@@ -1,43 +1,111 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/stebennett/bin-notifier/pkg/clients"
 	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/history"
+	"github.com/stebennett/bin-notifier/pkg/holidays"
 	"github.com/stebennett/bin-notifier/pkg/scraper"
+	"github.com/stebennett/bin-notifier/pkg/templates"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockScraper is a mock implementation of BinScraper for testing
 type mockScraper struct {
 	binTimes []scraper.BinTime
 	err      error
+	// ctx is the context of the last scrape.
+	ctx context.Context
 }
 
-func (m *mockScraper) ScrapeBinTimes(postcode string, address string) ([]scraper.BinTime, error) {
+func (m *mockScraper) ScrapeBinTimes(ctx context.Context, postcode string, address string) ([]scraper.BinTime, error) {
+	m.ctx = ctx
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return m.binTimes, m.err
 }
 
-// mockSMSClient is a mock implementation of SMSClient for testing
-type mockSMSClient struct {
-	calls []smsCall
+// mockChannel is a mock implementation of NotificationChannel for testing
+type mockChannel struct {
+	name  string
+	calls []channelCall
 	err   error
+	// errs, when set, are returned by successive calls before falling back to err.
+	errs []error
 }
 
-type smsCall struct {
-	from   string
-	to     string
-	body   string
+type channelCall struct {
+	msg    clients.Message
 	dryRun bool
 }
 
-func (m *mockSMSClient) SendSms(from string, to string, body string, dryRun bool) error {
-	m.calls = append(m.calls, smsCall{from: from, to: to, body: body, dryRun: dryRun})
+func (m *mockChannel) Name() string {
+	return m.name
+}
+
+func (m *mockChannel) Send(msg clients.Message, dryRun bool) error {
+	m.calls = append(m.calls, channelCall{msg: msg, dryRun: dryRun})
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return err
+	}
+	return m.err
+}
+
+// mockPublisher is a mock implementation of StatePublisher for testing
+type mockPublisher struct {
+	locations []string
+	binTimes  [][]scraper.BinTime
+	err       error
+}
+
+func (m *mockPublisher) PublishLocation(loc config.Location, binTimes []scraper.BinTime, today time.Time) error {
+	m.locations = append(m.locations, loc.Label)
+	m.binTimes = append(m.binTimes, binTimes)
 	return m.err
 }
 
+// mockHistory is an in-memory implementation of HistoryStore for testing
+type mockHistory struct {
+	sent map[string]bool
+	err  error
+}
+
+func newMockHistory() *mockHistory {
+	return &mockHistory{sent: make(map[string]bool)}
+}
+
+func historyKey(n history.Notification) string {
+	return fmt.Sprintf("%s|%s|%s|%v|%s|%s", n.Location, n.Date.Format("2006-01-02"), n.Kind, n.Types, n.Channel, n.Recipient)
+}
+
+func (m *mockHistory) Sent(n history.Notification) bool {
+	return m.sent[historyKey(n)]
+}
+
+func (m *mockHistory) Record(n history.Notification, at time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent[historyKey(n)] = true
+	return nil
+}
+
 func newMockFactory(scrapers map[string]*mockScraper) ScraperFactory {
 	return func(name string) (BinScraper, error) {
 		s, ok := scrapers[name]
@@ -71,8 +139,8 @@ func createTestConfig() config.Config {
 }
 
 func TestNotifier_SendsSmsWhenCollectionTomorrow(t *testing.T) {
-	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)    // Monday
-	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)  // Tuesday
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
@@ -80,11 +148,11 @@ func TestNotifier_SendsSmsWhenCollectionTomorrow(t *testing.T) {
 			{Type: "Recycling", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -94,29 +162,29 @@ func TestNotifier_SendsSmsWhenCollectionTomorrow(t *testing.T) {
 	assert.Len(t, results, 1)
 	r := results[0]
 	assert.Nil(t, r.Error)
-	assert.True(t, r.SMSSent)
+	assert.True(t, r.Sent())
 	assert.Equal(t, 2, len(r.Collections))
-	assert.Len(t, mockSMS.calls, 1)
-	assert.Equal(t, cfg.FromNumber, mockSMS.calls[0].from)
-	assert.Equal(t, cfg.ToNumber, mockSMS.calls[0].to)
-	assert.Contains(t, mockSMS.calls[0].body, "General Waste")
-	assert.Contains(t, mockSMS.calls[0].body, "Recycling")
+	assert.Len(t, mockCh.calls, 1)
+	assert.Equal(t, "Home", mockCh.calls[0].msg.Location)
+	assert.Equal(t, []string{"General Waste", "Recycling"}, mockCh.calls[0].msg.Types)
+	assert.Contains(t, mockCh.calls[0].msg.Body, "General Waste")
+	assert.Contains(t, mockCh.calls[0].msg.Body, "Recycling")
 }
 
 func TestNotifier_MessagePrefixedWithLabel(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)  // Tuesday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
 			{Type: "General Waste", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -129,18 +197,18 @@ func TestNotifier_MessagePrefixedWithLabel(t *testing.T) {
 
 func TestNotifier_SendsSmsOnRegularDayNoCollections(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	nextWeek := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)  // Monday +1 week
+	nextWeek := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC) // Monday +1 week
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
 			{Type: "General Waste", CollectionTime: nextWeek},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -150,25 +218,25 @@ func TestNotifier_SendsSmsOnRegularDayNoCollections(t *testing.T) {
 	assert.Len(t, results, 1)
 	r := results[0]
 	assert.Nil(t, r.Error)
-	assert.True(t, r.SMSSent)
+	assert.True(t, r.Sent())
 	assert.Contains(t, r.Message, "Expected General Waste, Recycling collection tomorrow (Tuesday) but none scheduled.")
 	assert.Equal(t, 0, len(r.Collections))
 }
 
 func TestNotifier_NoSmsWhenNoCollectionsAndNotRegularDay(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	nextWeek := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)  // Monday +1 week
+	nextWeek := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC) // Monday +1 week
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
 			{Type: "General Waste", CollectionTime: nextWeek},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -181,14 +249,14 @@ func TestNotifier_NoSmsWhenNoCollectionsAndNotRegularDay(t *testing.T) {
 	assert.Len(t, results, 1)
 	r := results[0]
 	assert.Nil(t, r.Error)
-	assert.False(t, r.SMSSent)
+	assert.False(t, r.Sent())
 	assert.Equal(t, 0, len(r.Collections))
-	assert.Len(t, mockSMS.calls, 0)
+	assert.Len(t, mockCh.calls, 0)
 }
 
 func TestNotifier_ScraperErrorContinuesOtherLocations(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)  // Tuesday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
 
 	failScr := &mockScraper{err: errors.New("scraper failed")}
 	okScr := &mockScraper{
@@ -196,15 +264,15 @@ func TestNotifier_ScraperErrorContinuesOtherLocations(t *testing.T) {
 			{Type: "Recycling", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{
 			"bracknell": failScr,
 			"wokingham": okScr,
 		}),
-		SMSClient: mockSMS,
-		Clock:     func() time.Time { return today },
+		Channels: []clients.NotificationChannel{mockCh},
+		Clock:    func() time.Time { return today },
 	}
 
 	cfg := config.Config{
@@ -222,24 +290,24 @@ func TestNotifier_ScraperErrorContinuesOtherLocations(t *testing.T) {
 	assert.NotNil(t, results[0].Error)
 	assert.Contains(t, results[0].Error.Error(), "scraper failed")
 	assert.Nil(t, results[1].Error)
-	assert.True(t, results[1].SMSSent)
-	assert.Len(t, mockSMS.calls, 1)
+	assert.True(t, results[1].Sent())
+	assert.Len(t, mockCh.calls, 1)
 }
 
 func TestNotifier_SmsErrorRecordedInResult(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)  // Tuesday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
 			{Type: "General Waste", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{err: errors.New("SMS send failed")}
+	mockCh := &mockChannel{name: "sms", err: errors.New("SMS send failed")}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -260,11 +328,11 @@ func TestNotifier_UsesTodayDateFromConfig(t *testing.T) {
 			{Type: "General Waste", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          clock,
 	}
 
@@ -274,17 +342,17 @@ func TestNotifier_UsesTodayDateFromConfig(t *testing.T) {
 
 	assert.Len(t, results, 1)
 	assert.Nil(t, results[0].Error)
-	assert.True(t, results[0].SMSSent)
+	assert.True(t, results[0].Sent())
 	assert.Contains(t, results[0].Message, "General Waste")
 }
 
 func TestNotifier_InvalidTodayDateReturnsError(t *testing.T) {
 	mockScr := &mockScraper{binTimes: []scraper.BinTime{}}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return time.Now() },
 	}
 
@@ -299,18 +367,18 @@ func TestNotifier_InvalidTodayDateReturnsError(t *testing.T) {
 
 func TestNotifier_DryRunPassedToSmsClient(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)  // Tuesday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
 			{Type: "General Waste", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -320,13 +388,13 @@ func TestNotifier_DryRunPassedToSmsClient(t *testing.T) {
 
 	assert.Len(t, results, 1)
 	assert.Nil(t, results[0].Error)
-	assert.Len(t, mockSMS.calls, 1)
-	assert.True(t, mockSMS.calls[0].dryRun)
+	assert.Len(t, mockCh.calls, 1)
+	assert.True(t, mockCh.calls[0].dryRun)
 }
 
 func TestNotifier_MultipleLocations(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)  // Tuesday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
 
 	homeScraper := &mockScraper{
 		binTimes: []scraper.BinTime{
@@ -338,15 +406,15 @@ func TestNotifier_MultipleLocations(t *testing.T) {
 			{Type: "Recycling", CollectionTime: tomorrow},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{
 			"bracknell": homeScraper,
 			"wokingham": officeScraper,
 		}),
-		SMSClient: mockSMS,
-		Clock:     func() time.Time { return today },
+		Channels: []clients.NotificationChannel{mockCh},
+		Clock:    func() time.Time { return today },
 	}
 
 	cfg := config.Config{
@@ -363,19 +431,19 @@ func TestNotifier_MultipleLocations(t *testing.T) {
 	assert.Len(t, results, 2)
 	assert.Nil(t, results[0].Error)
 	assert.Nil(t, results[1].Error)
-	assert.True(t, results[0].SMSSent)
-	assert.True(t, results[1].SMSSent)
-	assert.Len(t, mockSMS.calls, 2)
-	assert.Contains(t, mockSMS.calls[0].body, "Home:")
-	assert.Contains(t, mockSMS.calls[1].body, "Office:")
+	assert.True(t, results[0].Sent())
+	assert.True(t, results[1].Sent())
+	assert.Len(t, mockCh.calls, 2)
+	assert.Contains(t, mockCh.calls[0].msg.Body, "Home:")
+	assert.Contains(t, mockCh.calls[1].msg.Body, "Office:")
 }
 
 func TestNotifier_UnknownScraperRecordsError(t *testing.T) {
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return time.Now() },
 	}
 
@@ -401,11 +469,11 @@ func TestNotifier_FortnightlyOnWeekSendsWarning(t *testing.T) {
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -434,7 +502,7 @@ func TestNotifier_FortnightlyOnWeekSendsWarning(t *testing.T) {
 
 	assert.Len(t, results, 1)
 	assert.Nil(t, results[0].Error)
-	assert.True(t, results[0].SMSSent)
+	assert.True(t, results[0].Sent())
 	assert.Contains(t, results[0].Message, "Expected Garden Waste collection tomorrow (Friday)")
 }
 
@@ -445,11 +513,11 @@ func TestNotifier_FortnightlyOffWeekNoMessage(t *testing.T) {
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -478,24 +546,24 @@ func TestNotifier_FortnightlyOffWeekNoMessage(t *testing.T) {
 
 	assert.Len(t, results, 1)
 	assert.Nil(t, results[0].Error)
-	assert.False(t, results[0].SMSSent)
-	assert.Len(t, mockSMS.calls, 0)
+	assert.False(t, results[0].Sent())
+	assert.Len(t, mockCh.calls, 0)
 }
 
 func TestNotifier_MultipleCollectionDaysWarnings(t *testing.T) {
 	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
-	nextWeek := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)  // Monday +1 week
+	nextWeek := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC) // Monday +1 week
 
 	mockScr := &mockScraper{
 		binTimes: []scraper.BinTime{
 			{Type: "General Waste", CollectionTime: nextWeek},
 		},
 	}
-	mockSMS := &mockSMSClient{}
+	mockCh := &mockChannel{name: "sms"}
 
 	notifier := &Notifier{
 		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
-		SMSClient:      mockSMS,
+		Channels:       []clients.NotificationChannel{mockCh},
 		Clock:          func() time.Time { return today },
 	}
 
@@ -520,8 +588,1234 @@ func TestNotifier_MultipleCollectionDaysWarnings(t *testing.T) {
 
 	assert.Len(t, results, 1)
 	assert.Nil(t, results[0].Error)
-	assert.True(t, results[0].SMSSent)
-	assert.Len(t, mockSMS.calls, 2)
-	assert.Contains(t, mockSMS.calls[0].body, "Recycling")
-	assert.Contains(t, mockSMS.calls[1].body, "Food Waste")
+	assert.True(t, results[0].Sent())
+	assert.Len(t, mockCh.calls, 2)
+	assert.Contains(t, mockCh.calls[0].msg.Body, "Recycling")
+	assert.Contains(t, mockCh.calls[1].msg.Body, "Food Waste")
+}
+
+func TestNotifier_FansOutToAllChannels(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	mockScr := &mockScraper{
+		binTimes: []scraper.BinTime{
+			{Type: "General Waste", CollectionTime: tomorrow},
+		},
+	}
+	sms := &mockChannel{name: "sms"}
+	email := &mockChannel{name: "email"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{sms, email},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createTestConfig())
+
+	assert.Len(t, results, 1)
+	assert.Nil(t, results[0].Error)
+	assert.Len(t, sms.calls, 1)
+	assert.Len(t, email.calls, 1)
+	assert.Equal(t, []ChannelResult{
+		{Channel: "sms", Sent: true, Attempts: 1},
+		{Channel: "email", Sent: true, Attempts: 1},
+	}, results[0].Channels)
+}
+
+func TestNotifier_ChannelFailureRecordedPerChannel(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	mockScr := &mockScraper{
+		binTimes: []scraper.BinTime{
+			{Type: "General Waste", CollectionTime: tomorrow},
+		},
+	}
+	failing := &mockChannel{name: "sms", err: errors.New("SMS send failed")}
+	working := &mockChannel{name: "email"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{failing, working},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createTestConfig())
+
+	assert.Len(t, results, 1)
+	r := results[0]
+	assert.NotNil(t, r.Error)
+	assert.Contains(t, r.Error.Error(), "sms error: SMS send failed")
+	assert.True(t, r.Sent())
+	assert.Len(t, working.calls, 1)
+	assert.Len(t, r.Channels, 2)
+	assert.False(t, r.Channels[0].Sent)
+	assert.NotNil(t, r.Channels[0].Error)
+	assert.True(t, r.Channels[1].Sent)
+}
+
+func TestNotifier_MessageDescribesCollection(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	mockScr := &mockScraper{
+		binTimes: []scraper.BinTime{
+			{Type: "General Waste", CollectionTime: tomorrow},
+		},
+	}
+	mockCh := &mockChannel{name: "webhook"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	notifier.Run(createTestConfig())
+
+	assert.Len(t, mockCh.calls, 1)
+	msg := mockCh.calls[0].msg
+	assert.Equal(t, clients.KindCollection, msg.Kind)
+	assert.Equal(t, "RG12 1AB", msg.PostCode)
+	assert.Equal(t, tomorrow, msg.Date)
+}
+
+func TestNotifier_WarningMessageKind(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{}}
+	mockCh := &mockChannel{name: "webhook"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	notifier.Run(createTestConfig())
+
+	assert.Len(t, mockCh.calls, 1)
+	msg := mockCh.calls[0].msg
+	assert.Equal(t, clients.KindScheduleWarning, msg.Kind)
+	assert.Equal(t, []string{"General Waste", "Recycling"}, msg.Types)
+}
+
+func TestNotifier_PublishesScrapedState(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	binTimes := []scraper.BinTime{
+		{Type: "General Waste", CollectionTime: tomorrow},
+	}
+	mockScr := &mockScraper{binTimes: binTimes}
+	publisher := &mockPublisher{}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{&mockChannel{name: "sms"}},
+		Publisher:      publisher,
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createTestConfig())
+
+	assert.Nil(t, results[0].Error)
+	assert.Equal(t, []string{"Home"}, publisher.locations)
+	assert.Equal(t, [][]scraper.BinTime{binTimes}, publisher.binTimes)
+}
+
+func TestNotifier_PublishErrorStillSendsNotification(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	mockScr := &mockScraper{
+		binTimes: []scraper.BinTime{
+			{Type: "General Waste", CollectionTime: tomorrow},
+		},
+	}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Publisher:      &mockPublisher{err: errors.New("broker unavailable")},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createTestConfig())
+
+	assert.NotNil(t, results[0].Error)
+	assert.Contains(t, results[0].Error.Error(), "mqtt error: broker unavailable")
+	assert.True(t, results[0].Sent())
+	assert.Len(t, mockCh.calls, 1)
+}
+
+func TestNotifier_PublishErrorKeptWithSendError(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "General Waste", CollectionTime: tomorrow}}}
+	failing := &mockChannel{name: "sms", err: errors.New("twilio down")}
+	working := &mockChannel{name: "email"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{failing, working},
+		Publisher:      &mockPublisher{err: errors.New("broker unavailable")},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createTestConfig())
+
+	require.NotNil(t, results[0].Error)
+	assert.Contains(t, results[0].Error.Error(), "[Home] mqtt error: broker unavailable")
+	assert.Contains(t, results[0].Error.Error(), "sms error: twilio down")
+	assert.Len(t, working.calls, 1)
+	assert.True(t, results[0].Sent())
+}
+
+func TestNotifier_DryRunSkipsPublish(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+
+	publisher := &mockPublisher{}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {}}),
+		Channels:       []clients.NotificationChannel{&mockChannel{name: "sms"}},
+		Publisher:      publisher,
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.DryRun = true
+	notifier.Run(cfg)
+
+	assert.Empty(t, publisher.locations)
+}
+
+func TestNotifier_RendersConfiguredTemplates(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	mockScr := &mockScraper{
+		binTimes: []scraper.BinTime{
+			{Type: "General Waste", CollectionTime: tomorrow},
+			{Type: "Recycling", CollectionTime: tomorrow},
+		},
+	}
+	sms := &mockChannel{name: "sms"}
+	slack := &mockChannel{name: "slack"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{sms, slack},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.Templates.Collection = `{{.Label}} ({{.Weekday}}): {{join .Types " + "}}`
+	cfg.Channels = []config.ChannelConfig{
+		{Type: "slack", Name: "slack", Templates: config.MessageTemplates{
+			Collection: `{{range .Types}}{{emoji .}}{{end}} {{.Source}}`,
+		}},
+	}
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Error)
+	assert.Equal(t, "Home (Tuesday): General Waste + Recycling", results[0].Message)
+	require.Len(t, sms.calls, 1)
+	assert.Equal(t, "Home (Tuesday): General Waste + Recycling", sms.calls[0].msg.Body)
+	require.Len(t, slack.calls, 1)
+	assert.Equal(t, "🗑️♻️ scraped", slack.calls[0].msg.Body)
+}
+
+func TestNotifier_LocationTemplateOverridesGlobal(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.Templates.ScheduleWarning = "global"
+	cfg.Locations[0].Templates.ScheduleWarning = `{{.Label}}: check {{.Source}} {{title (join .Types " and ")}} on {{.Date.Format "2 Jan"}}`
+	results := notifier.Run(cfg)
+
+	assert.Nil(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, clients.KindScheduleWarning, mockCh.calls[0].msg.Kind)
+	assert.Equal(t, "Home: check expected General Waste And Recycling on 16 Jan", mockCh.calls[0].msg.Body)
+}
+
+func TestMessageTemplate_Precedence(t *testing.T) {
+	cfg := config.Config{
+		Templates: config.MessageTemplates{Collection: "global", ScheduleWarning: "global warning"},
+		Channels: []config.ChannelConfig{
+			{Name: "slack", Templates: config.MessageTemplates{Collection: "channel"}},
+		},
+	}
+	loc := config.Location{Templates: config.MessageTemplates{Collection: "location"}}
+
+	assert.Equal(t, "channel", messageTemplate(cfg, loc, "slack", clients.KindCollection))
+	assert.Equal(t, "location", messageTemplate(cfg, loc, "sms", clients.KindCollection))
+	assert.Equal(t, "location", messageTemplate(cfg, loc, "", clients.KindCollection))
+	assert.Equal(t, "global warning", messageTemplate(cfg, loc, "slack", clients.KindScheduleWarning))
+	assert.Equal(t, templates.DefaultCollection, messageTemplate(config.Config{}, config.Location{}, "sms", clients.KindCollection))
+	assert.Equal(t, templates.DefaultScheduleWarning, messageTemplate(config.Config{}, config.Location{}, "", clients.KindScheduleWarning))
+}
+
+func TestNotifier_RoutesToLocationRecipients(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	homeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "General Waste", CollectionTime: tomorrow}}}
+	officeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	sms := &mockChannel{name: "twilio"}
+	push := &mockChannel{name: "ntfy"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": homeScr, "wokingham": officeScr}),
+		Channels:       []clients.NotificationChannel{sms, push},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.Recipients = []config.Recipient{{Channel: "twilio", Address: "+447700900000"}}
+	cfg.Locations[0].Recipients = []config.Recipient{{Channel: "twilio", Address: "+447700900001"}}
+	cfg.Locations = append(cfg.Locations, config.Location{
+		Label:   "Office",
+		Scraper: "wokingham",
+		Recipients: []config.Recipient{
+			{Channel: "twilio", Address: "+447700900002"},
+			{Channel: "twilio", Address: "+447700900000"},
+		},
+	})
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 2)
+	require.Len(t, sms.calls, 2)
+	assert.Equal(t, []string{"+447700900000", "+447700900001"}, sms.calls[0].msg.To)
+	assert.Equal(t, []string{"+447700900000", "+447700900002"}, sms.calls[1].msg.To)
+	require.Len(t, push.calls, 2)
+	assert.Nil(t, push.calls[0].msg.To)
+}
+
+func TestNotifier_RecipientTypeFilter(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "General Waste", CollectionTime: tomorrow}}}
+	sms := &mockChannel{name: "twilio"}
+	email := &mockChannel{name: "email"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{sms, email},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].Recipients = []config.Recipient{
+		{Channel: "twilio", Address: "+447700900001", Types: []string{"garden waste"}},
+		{Channel: "email", Address: "all@example.com"},
+		{Channel: "email", Address: "general@example.com", Types: []string{"general waste"}},
+	}
+	results := notifier.Run(cfg)
+
+	assert.Nil(t, results[0].Error)
+	assert.Empty(t, sms.calls)
+	require.Len(t, email.calls, 1)
+	assert.Equal(t, []string{"all@example.com", "general@example.com"}, email.calls[0].msg.To)
+	require.Len(t, results[0].Channels, 1)
+	assert.Equal(t, "email", results[0].Channels[0].Channel)
+}
+
+func createDigestTestConfig() config.Config {
+	cfg := createTestConfig()
+	cfg.Digest = true
+	cfg.Locations = append(cfg.Locations, config.Location{
+		Label:   "Office",
+		Scraper: "wokingham",
+		CollectionDays: []config.CollectionDay{
+			{Day: time.Tuesday, Types: []string{"Food Waste"}, EveryNWeeks: 1},
+		},
+	})
+	return cfg
+}
+
+func TestNotifier_DigestCombinesLocations(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	homeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	officeScr := &mockScraper{} // nothing scheduled on a collection day
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": homeScr, "wokingham": officeScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createDigestTestConfig())
+
+	require.Len(t, results, 2)
+	require.Len(t, mockCh.calls, 1)
+	digest := mockCh.calls[0].msg
+	assert.Equal(t, clients.KindDigest, digest.Kind)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling\n"+
+		"Office: Expected Food Waste collection tomorrow (Tuesday) but none scheduled.", digest.Body)
+	assert.Equal(t, []string{"Recycling", "Food Waste"}, digest.Types)
+	assert.Equal(t, tomorrow, digest.Date)
+	assert.Empty(t, digest.Location)
+
+	for _, r := range results {
+		assert.Nil(t, r.Error)
+		assert.True(t, r.Sent())
+		assert.Len(t, r.Channels, 1)
+	}
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling", results[0].Message)
+}
+
+func TestNotifier_DigestPerRecipient(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	homeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	officeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Food Waste", CollectionTime: tomorrow}}}
+	mockCh := &mockChannel{name: "twilio"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": homeScr, "wokingham": officeScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createDigestTestConfig()
+	cfg.Recipients = []config.Recipient{{Channel: "twilio", Address: "+447700900000"}}
+	cfg.Locations[1].Recipients = []config.Recipient{{Channel: "twilio", Address: "+447700900002"}}
+	results := notifier.Run(cfg)
+
+	require.Len(t, mockCh.calls, 2)
+	assert.Equal(t, []string{"+447700900000"}, mockCh.calls[0].msg.To)
+	assert.Contains(t, mockCh.calls[0].msg.Body, "Home:")
+	assert.Contains(t, mockCh.calls[0].msg.Body, "Office:")
+	assert.Equal(t, []string{"+447700900002"}, mockCh.calls[1].msg.To)
+	assert.Equal(t, "Office: Tomorrows bin collections are: Food Waste", mockCh.calls[1].msg.Body)
+	assert.Equal(t, "Office", mockCh.calls[1].msg.Location)
+	assert.Len(t, results[0].Channels, 1)
+	assert.Len(t, results[1].Channels, 2)
+}
+
+func TestNotifier_DigestKeepsTelegramRoutes(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+	sent := make(map[string][]string)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ChatID string `json:"chat_id"`
+			Text   string `json:"text"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		sent[req.ChatID] = append(sent[req.ChatID], req.Text)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+
+	cfg := createDigestTestConfig()
+	cfg.Locations[0].TelegramChatIDs = []string{"100"}
+	cfg.Locations[1].TelegramChatIDs = []string{"200"}
+	telegram, err := clients.NewChannel(config.ChannelConfig{
+		Type:     "telegram",
+		Telegram: config.TelegramConfig{Token: "token", BaseURL: api.URL},
+	}, cfg)
+	require.NoError(t, err)
+
+	homeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	officeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Food Waste", CollectionTime: tomorrow}}}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": homeScr, "wokingham": officeScr}),
+		Channels:       []clients.NotificationChannel{telegram},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(cfg)
+
+	for _, r := range results {
+		assert.Nil(t, r.Error)
+		assert.True(t, r.Sent())
+	}
+	require.Len(t, sent["100"], 1)
+	assert.Contains(t, sent["100"][0], "Recycling")
+	assert.NotContains(t, sent["100"][0], "Food Waste")
+	require.Len(t, sent["200"], 1)
+	assert.Contains(t, sent["200"][0], "Food Waste")
+	assert.NotContains(t, sent["200"][0], "Recycling")
+}
+
+func TestNotifier_DigestErrorReportedForEachLocation(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	homeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	officeScr := &mockScraper{err: errors.New("council site down")}
+	failing := &mockChannel{name: "sms", err: errors.New("twilio down")}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": homeScr, "wokingham": officeScr}),
+		Channels:       []clients.NotificationChannel{failing},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createDigestTestConfig())
+
+	require.Len(t, failing.calls, 1)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling", failing.calls[0].msg.Body)
+	assert.NotNil(t, results[0].Error)
+	assert.Contains(t, results[0].Error.Error(), "[Home] sms error: twilio down")
+	assert.False(t, results[0].Sent())
+	assert.NotNil(t, results[1].Error)
+	assert.Contains(t, results[1].Error.Error(), "scrape error")
+	assert.Empty(t, results[1].Channels)
+}
+
+func TestNotifier_DigestNothingToSend(t *testing.T) {
+	today := time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC) // Tuesday
+
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {}, "wokingham": {}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	results := notifier.Run(createDigestTestConfig())
+
+	assert.Empty(t, mockCh.calls)
+	for _, r := range results {
+		assert.Nil(t, r.Error)
+		assert.False(t, r.Sent())
+	}
+}
+
+func newHistoryTestNotifier(today time.Time, store HistoryStore, channels ...clients.NotificationChannel) *Notifier {
+	tomorrow := today.AddDate(0, 0, 1)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	return &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       channels,
+		History:        store,
+		Clock:          func() time.Time { return today },
+	}
+}
+
+func TestNotifier_HistorySkipsRepeatRun(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms"}
+	notifier := newHistoryTestNotifier(today, newMockHistory(), mockCh)
+
+	first := notifier.Run(createTestConfig())
+	second := notifier.Run(createTestConfig())
+
+	assert.Len(t, mockCh.calls, 1)
+	assert.True(t, first[0].Sent())
+	assert.Nil(t, second[0].Error)
+	assert.False(t, second[0].Sent())
+	require.Len(t, second[0].Channels, 1)
+	assert.True(t, second[0].Channels[0].Skipped)
+}
+
+func TestNotifier_HistoryRetriesFailedChannelsOnly(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	sms := &mockChannel{name: "sms"}
+	email := &mockChannel{name: "email", err: errors.New("smtp down")}
+	notifier := newHistoryTestNotifier(today, newMockHistory(), sms, email)
+
+	first := notifier.Run(createTestConfig())
+	assert.NotNil(t, first[0].Error)
+
+	email.err = nil
+	second := notifier.Run(createTestConfig())
+
+	assert.Nil(t, second[0].Error)
+	assert.Len(t, sms.calls, 1)
+	assert.Len(t, email.calls, 2)
+	assert.True(t, second[0].Channels[0].Skipped)
+	assert.True(t, second[0].Channels[1].Sent)
+}
+
+func TestNotifier_HistoryForceResends(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms"}
+	notifier := newHistoryTestNotifier(today, newMockHistory(), mockCh)
+
+	notifier.Run(createTestConfig())
+	cfg := createTestConfig()
+	cfg.Force = true
+	results := notifier.Run(cfg)
+
+	assert.Len(t, mockCh.calls, 2)
+	assert.True(t, results[0].Sent())
+}
+
+func TestNotifier_HistoryDryRunDoesNotRecord(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms"}
+	store := newMockHistory()
+	notifier := newHistoryTestNotifier(today, store, mockCh)
+
+	cfg := createTestConfig()
+	cfg.DryRun = true
+	notifier.Run(cfg)
+
+	assert.Empty(t, store.sent)
+}
+
+func TestNotifier_HistoryPerRecipient(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "twilio"}
+	notifier := newHistoryTestNotifier(today, newMockHistory(), mockCh)
+
+	cfg := createTestConfig()
+	cfg.Recipients = []config.Recipient{{Channel: "twilio", Address: "+447700900001"}}
+	notifier.Run(cfg)
+
+	cfg.Recipients = append(cfg.Recipients, config.Recipient{Channel: "twilio", Address: "+447700900002"})
+	notifier.Run(cfg)
+
+	require.Len(t, mockCh.calls, 2)
+	assert.Equal(t, []string{"+447700900001"}, mockCh.calls[0].msg.To)
+	assert.Equal(t, []string{"+447700900002"}, mockCh.calls[1].msg.To)
+}
+
+func TestNotifier_HistoryRecordErrorReported(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms"}
+	store := newMockHistory()
+	store.err = errors.New("disk full")
+	notifier := newHistoryTestNotifier(today, store, mockCh)
+
+	results := notifier.Run(createTestConfig())
+
+	assert.True(t, results[0].Sent())
+	assert.NotNil(t, results[0].Error)
+	assert.Contains(t, results[0].Error.Error(), "history error: disk full")
+}
+
+func TestNotifier_HistorySkipsDigestMessages(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)   // Monday
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+
+	homeScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	officeScr := &mockScraper{err: errors.New("council site down")}
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": homeScr, "wokingham": officeScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		History:        newMockHistory(),
+		Clock:          func() time.Time { return today },
+	}
+
+	notifier.Run(createDigestTestConfig())
+	officeScr.err = nil
+	results := notifier.Run(createDigestTestConfig())
+
+	require.Len(t, mockCh.calls, 2)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling", mockCh.calls[0].msg.Body)
+	assert.Equal(t, "Office: Expected Food Waste collection tomorrow (Tuesday) but none scheduled.", mockCh.calls[1].msg.Body)
+	require.Len(t, results[0].Channels, 1)
+	assert.True(t, results[0].Channels[0].Skipped)
+	assert.True(t, results[1].Sent())
+}
+
+func createRetryTestConfig() config.Config {
+	cfg := createTestConfig()
+	cfg.Retry = config.RetryConfig{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: 30 * time.Second}
+	return cfg
+}
+
+func TestNotifier_RetriesTransientFailures(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms", errs: []error{errors.New("503 service unavailable")}}
+	var slept []time.Duration
+	notifier := newHistoryTestNotifier(today, nil, mockCh)
+	notifier.Sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	results := notifier.Run(createRetryTestConfig())
+
+	assert.Nil(t, results[0].Error)
+	assert.Len(t, mockCh.calls, 2)
+	assert.Len(t, slept, 1)
+	assert.Equal(t, []ChannelResult{{Channel: "sms", Sent: true, Attempts: 2}}, results[0].Channels)
+}
+
+func TestNotifier_GivesUpAfterMaxAttempts(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms", err: errors.New("503 service unavailable")}
+	notifier := newHistoryTestNotifier(today, nil, mockCh)
+	notifier.Sleep = func(time.Duration) {}
+
+	results := notifier.Run(createRetryTestConfig())
+
+	assert.NotNil(t, results[0].Error)
+	assert.Len(t, mockCh.calls, 3)
+	assert.Equal(t, 3, results[0].Channels[0].Attempts)
+	assert.False(t, results[0].Sent())
+}
+
+func TestNotifier_DoesNotRetryPermanentFailures(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms", err: clients.Permanent(errors.New("invalid 'To' number"))}
+	notifier := newHistoryTestNotifier(today, nil, mockCh)
+	notifier.Sleep = func(time.Duration) { t.Fatal("unexpected retry") }
+
+	results := notifier.Run(createRetryTestConfig())
+
+	assert.Len(t, mockCh.calls, 1)
+	assert.Equal(t, 1, results[0].Channels[0].Attempts)
+	assert.Contains(t, results[0].Error.Error(), "invalid 'To' number")
+}
+
+func TestNotifier_RetriesOnlyFailedRecipients(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "twilio", errs: []error{
+		&clients.RecipientError{Recipient: "+447700900002", Err: errors.New("timeout")},
+	}}
+	store := newMockHistory()
+	notifier := newHistoryTestNotifier(today, store, mockCh)
+	notifier.Sleep = func(time.Duration) {}
+
+	cfg := createRetryTestConfig()
+	cfg.Recipients = []config.Recipient{
+		{Channel: "twilio", Address: "+447700900001"},
+		{Channel: "twilio", Address: "+447700900002"},
+	}
+	results := notifier.Run(cfg)
+
+	assert.Nil(t, results[0].Error)
+	require.Len(t, mockCh.calls, 2)
+	assert.Equal(t, []string{"+447700900001", "+447700900002"}, mockCh.calls[0].msg.To)
+	assert.Equal(t, []string{"+447700900002"}, mockCh.calls[1].msg.To)
+	assert.Len(t, store.sent, 2)
+}
+
+func TestNotifier_DefaultRecipientsPartialFailureNotRecorded(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	var chats []string
+	failing := true
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ChatID string `json:"chat_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		chats = append(chats, req.ChatID)
+		if failing && req.ChatID == "200" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"ok":false,"description":"internal error"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+
+	telegram := clients.NewTelegramChannel("telegram", config.TelegramConfig{
+		Token: "token", ChatIDs: []string{"100", "200"}, BaseURL: api.URL,
+	}, nil)
+	store := newMockHistory()
+	notifier := newHistoryTestNotifier(today, store, telegram)
+	notifier.Sleep = func(time.Duration) {}
+
+	results := notifier.Run(createRetryTestConfig())
+
+	assert.NotNil(t, results[0].Error)
+	assert.Equal(t, []string{"100", "200", "200", "200"}, chats, "only the failed chat is retried")
+	assert.Empty(t, store.sent, "a partly failed send to the default chats is not recorded")
+
+	failing = false
+	chats = nil
+	results = notifier.Run(createRetryTestConfig())
+
+	assert.Nil(t, results[0].Error)
+	assert.True(t, results[0].Sent())
+	assert.Equal(t, []string{"100", "200"}, chats)
+	assert.Len(t, store.sent, 1)
+}
+
+func TestNotifier_RecordsRecipientsDeliveredBeforeFailure(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "twilio", err: &clients.RecipientError{
+		Recipient: "+447700900002", Err: clients.Permanent(errors.New("unsubscribed")),
+	}}
+	store := newMockHistory()
+	notifier := newHistoryTestNotifier(today, store, mockCh)
+
+	cfg := createRetryTestConfig()
+	cfg.Recipients = []config.Recipient{
+		{Channel: "twilio", Address: "+447700900001"},
+		{Channel: "twilio", Address: "+447700900002"},
+	}
+	results := notifier.Run(cfg)
+
+	assert.NotNil(t, results[0].Error)
+	assert.Len(t, mockCh.calls, 1)
+	require.Len(t, store.sent, 1)
+	for key := range store.sent {
+		assert.Contains(t, key, "+447700900001")
+	}
+}
+
+func TestNotifier_ContinuesAfterFailedWarning(t *testing.T) {
+	today := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) // Monday
+
+	mockCh := &mockChannel{name: "sms", errs: []error{clients.Permanent(errors.New("rejected"))}}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createRetryTestConfig()
+	cfg.Locations[0].CollectionDays = append(cfg.Locations[0].CollectionDays, config.CollectionDay{
+		Day: time.Tuesday, Types: []string{"Garden Waste"}, EveryNWeeks: 1,
+	})
+	results := notifier.Run(cfg)
+
+	assert.Len(t, mockCh.calls, 2)
+	assert.NotNil(t, results[0].Error)
+	assert.True(t, results[0].Sent())
+	require.Len(t, results[0].Channels, 2)
+	assert.False(t, results[0].Channels[0].Sent)
+	assert.True(t, results[0].Channels[1].Sent)
+}
+
+func createReminderTestConfig() config.Config {
+	cfg := createTestConfig()
+	cfg.Locations[0].Reminders = []config.Reminder{
+		{Offset: -1, Action: config.ActionPutOut, TimeOfDay: 19 * time.Hour},
+		{Offset: 0, Action: config.ActionBringIn, TimeOfDay: 17 * time.Hour},
+	}
+	return cfg
+}
+
+func TestNotifier_RemindersWaitForTheirTime(t *testing.T) {
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tomorrow}}}
+	mockCh := &mockChannel{name: "sms"}
+	now := time.Date(2024, 1, 15, 18, 59, 0, 0, time.UTC) // Monday evening
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return now },
+	}
+
+	results := notifier.Run(createReminderTestConfig())
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	assert.Empty(t, mockCh.calls)
+
+	now = time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC)
+	results = notifier.Run(createReminderTestConfig())
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, clients.KindCollection, mockCh.calls[0].msg.Kind)
+	assert.Equal(t, tomorrow, mockCh.calls[0].msg.Date)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling", mockCh.calls[0].msg.Body)
+}
+
+func TestNotifier_BringInReminderOnCollectionDay(t *testing.T) {
+	today := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: today}}}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return time.Date(2024, 1, 16, 17, 30, 0, 0, time.UTC) },
+	}
+
+	results := notifier.Run(createReminderTestConfig())
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	msg := mockCh.calls[0].msg
+	assert.Equal(t, clients.KindBringIn, msg.Kind)
+	assert.Equal(t, today, msg.Date)
+	assert.Equal(t, []string{"Recycling"}, msg.Types)
+	assert.Equal(t, "Home: Bring your bins in (Recycling)", msg.Body)
+}
+
+func TestNotifier_BringInFallsBackToSchedule(t *testing.T) {
+	nextWeek := time.Date(2024, 1, 23, 0, 0, 0, 0, time.UTC)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: nextWeek}}}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return time.Date(2024, 1, 16, 17, 30, 0, 0, time.UTC) }, // Tuesday
+	}
+
+	cfg := createReminderTestConfig()
+	cfg.Locations[0].Templates.BringIn = "{{.Source}}: {{join .Types \" and \"}}"
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, "expected: General Waste and Recycling", mockCh.calls[0].msg.Body)
+}
+
+func TestNotifier_SendsEveryDueReminder(t *testing.T) {
+	today := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC) // Tuesday
+	nextDay := today.AddDate(0, 0, 1)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{
+		{Type: "General Waste", CollectionTime: today},
+		{Type: "Food Waste", CollectionTime: nextDay},
+	}}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return time.Date(2024, 1, 16, 20, 0, 0, 0, time.UTC) },
+	}
+
+	results := notifier.Run(createReminderTestConfig())
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 2)
+	assert.Equal(t, clients.KindCollection, mockCh.calls[0].msg.Kind)
+	assert.Equal(t, nextDay, mockCh.calls[0].msg.Date)
+	assert.Equal(t, clients.KindBringIn, mockCh.calls[1].msg.Kind)
+	assert.Equal(t, today, mockCh.calls[1].msg.Date)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Food Waste; Home: Bring your bins in (General Waste)", results[0].Message)
+}
+
+func TestDueReminders_DefaultsToDayBefore(t *testing.T) {
+	due := dueReminders(config.Location{}, 0)
+
+	assert.Equal(t, []config.Reminder{{Offset: -1, Action: config.ActionPutOut}}, due)
+}
+
+func TestNotifier_NotifyDaysBefore(t *testing.T) {
+	today := time.Date(2024, 1, 14, 10, 0, 0, 0, time.UTC) // Sunday
+	tuesday := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Garden Waste", CollectionTime: tuesday}}}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].NotifyDaysBefore = []int{1, 2}
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	msg := mockCh.calls[0].msg
+	assert.Equal(t, tuesday, msg.Date)
+	assert.Equal(t, "in 2 days", msg.When)
+	assert.Equal(t, "Home: bin collection in 2 days", msg.Title)
+	assert.Equal(t, "Home: Bin collections in 2 days (Tuesday) are: Garden Waste", msg.Body)
+}
+
+func TestNotifier_NotifyDaysBeforeWarnsOnExpectedDay(t *testing.T) {
+	today := time.Date(2024, 1, 14, 10, 0, 0, 0, time.UTC) // Sunday
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{}}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return today },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].NotifyDaysBefore = []int{2}
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, clients.KindScheduleWarning, mockCh.calls[0].msg.Kind)
+	assert.Equal(t, "Home: Expected General Waste, Recycling collection in 2 days (Tuesday) but none scheduled.", mockCh.calls[0].msg.Body)
+}
+
+func TestRemindersFor_CombinesNotifyDaysBefore(t *testing.T) {
+	loc := config.Location{
+		Reminders:        []config.Reminder{{Offset: 0, Action: config.ActionBringIn, TimeOfDay: 17 * time.Hour}},
+		NotifyDaysBefore: []int{1, 3},
+	}
+
+	assert.Equal(t, []config.Reminder{
+		{Offset: 0, Action: config.ActionBringIn, TimeOfDay: 17 * time.Hour},
+		{Offset: -1, Action: config.ActionPutOut},
+		{Offset: -3, Action: config.ActionPutOut},
+	}, remindersFor(loc))
+}
+
+func TestDueReminders_RemovesDuplicates(t *testing.T) {
+	loc := config.Location{
+		Reminders: []config.Reminder{
+			{Offset: -1, Action: config.ActionPutOut, TimeOfDay: 18 * time.Hour},
+			{Offset: -1, Action: config.ActionPutOut},
+			{Offset: 1, Action: config.ActionBringIn},
+		},
+		NotifyDaysBefore: []int{1, 2, 2},
+	}
+
+	assert.Equal(t, []config.Reminder{
+		{Offset: -1, Action: config.ActionPutOut, TimeOfDay: 18 * time.Hour},
+		{Offset: 1, Action: config.ActionBringIn},
+		{Offset: -2, Action: config.ActionPutOut},
+	}, dueReminders(loc, 19*time.Hour))
+	assert.Equal(t, []config.Reminder{
+		{Offset: -1, Action: config.ActionPutOut},
+		{Offset: 1, Action: config.ActionBringIn},
+		{Offset: -2, Action: config.ActionPutOut},
+	}, dueReminders(loc, 10*time.Hour))
+}
+
+func TestNotifier_OverlappingRemindersSendOnce(t *testing.T) {
+	today := time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC) // Monday
+	mockCh := &mockChannel{name: "sms"}
+	notifier := newHistoryTestNotifier(today, nil, mockCh)
+
+	cfg := createTestConfig()
+	cfg.Locations[0].Reminders = []config.Reminder{{Offset: -1, Action: config.ActionPutOut, TimeOfDay: 18 * time.Hour}}
+	cfg.Locations[0].NotifyDaysBefore = []int{1}
+	results := notifier.Run(cfg)
+
+	assert.NoError(t, results[0].Error)
+	assert.Len(t, mockCh.calls, 1)
+	assert.Len(t, results[0].Collections, 1)
+}
+
+func TestNotifier_UsesConfiguredTimezone(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	// 00:30 BST on Tuesday 16 June is still Monday in UTC, so tomorrow is Wednesday.
+	now := time.Date(2026, 6, 15, 23, 30, 0, 0, time.UTC)
+	wednesday := time.Date(2026, 6, 17, 0, 0, 0, 0, london)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{
+		{Type: "General Waste", CollectionTime: time.Date(2026, 6, 16, 0, 0, 0, 0, london)},
+		{Type: "Recycling", CollectionTime: wednesday},
+	}}
+	mockCh := &mockChannel{name: "sms"}
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return now },
+	}
+
+	cfg := createTestConfig()
+	cfg.Zone = london
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	assert.Equal(t, []string{"Recycling"}, results[0].Collections)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, wednesday, mockCh.calls[0].msg.Date)
+}
+
+func TestNotifier_ReminderTimeUsesConfiguredTimezone(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	tuesday := time.Date(2026, 6, 16, 0, 0, 0, 0, london)
+	mockScr := &mockScraper{binTimes: []scraper.BinTime{{Type: "Recycling", CollectionTime: tuesday}}}
+	mockCh := &mockChannel{name: "sms"}
+	// 18:30 UTC is 19:30 BST, after the 19:00 reminder.
+	now := time.Date(2026, 6, 15, 18, 30, 0, 0, time.UTC)
+
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mockScr}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return now },
+	}
+
+	cfg := createReminderTestConfig()
+	cfg.Zone = london
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, tuesday, mockCh.calls[0].msg.Date)
+}
+
+func TestNotifier_NoWarningForCollectionMovedByBankHoliday(t *testing.T) {
+	easterMonday := time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC)
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {binTimes: []scraper.BinTime{}}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return easterMonday },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].HolidayShift = config.HolidayShift{Days: 1, Scope: config.HolidayScopeWeek}
+	cfg.HolidayCalendar = holidays.New(easterMonday)
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	assert.Empty(t, mockCh.calls)
+
+	// The day after, the moved Wednesday collection is expected instead.
+	notifier.Clock = func() time.Time { return easterMonday.AddDate(0, 0, 1) }
+	results = notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, clients.KindScheduleWarning, mockCh.calls[0].msg.Kind)
+	assert.Equal(t, "Home: Expected General Waste, Recycling collection tomorrow (Wednesday) but none scheduled.", mockCh.calls[0].msg.Body)
+}
+
+func TestNotifier_WarnsPastHolidayCalendar(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	cal, err := holidays.Named("england-and-wales")
+	require.NoError(t, err)
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {binTimes: []scraper.BinTime{}}}),
+		Channels:       []clients.NotificationChannel{&mockChannel{name: "sms"}},
+		Clock:          func() time.Time { return time.Date(2028, 12, 31, 10, 0, 0, 0, time.UTC) },
+	}
+
+	cfg := createTestConfig()
+	cfg.Holidays.Calendar = "england-and-wales"
+	cfg.HolidayCalendar = cal
+	cfg.Locations[0].HolidayShift = config.HolidayShift{Days: 1, Scope: config.HolidayScopeWeek}
+	notifier.Run(cfg)
+
+	assert.Contains(t, logs.String(), "[Home] WARNING: the england-and-wales holiday calendar has no bank holidays for 2029")
+
+	// Without a shift rule the calendar isn't used.
+	logs.Reset()
+	cfg.Locations[0].HolidayShift = config.HolidayShift{}
+	notifier.Run(cfg)
+
+	assert.NotContains(t, logs.String(), "WARNING")
+}
+
+func TestNotifier_AppliesScheduleExceptions(t *testing.T) {
+	monday := time.Date(2026, 12, 21, 10, 0, 0, 0, time.UTC)
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {binTimes: []scraper.BinTime{}}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return monday },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].Exceptions = []config.Exception{
+		{From: "2026-12-22", To: "2026-12-24", Types: []string{"Recycling"}},
+	}
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, "Home: Expected General Waste collection tomorrow (Tuesday) but none scheduled.", mockCh.calls[0].msg.Body)
+
+	// Recycling is expected on the date it was moved to.
+	mockCh.calls = nil
+	notifier.Clock = func() time.Time { return monday.AddDate(0, 0, 2) }
+	results = notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, "Home: Expected Recycling collection tomorrow (Thursday) but none scheduled.", mockCh.calls[0].msg.Body)
+}
+
+func TestNotifier_NoWarningOutOfSeason(t *testing.T) {
+	monday := time.Date(2027, 1, 11, 10, 0, 0, 0, time.UTC)
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {binTimes: []scraper.BinTime{}}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return monday },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].CollectionDays = []config.CollectionDay{
+		{Day: time.Tuesday, Types: []string{"Garden Waste"}, EveryNWeeks: 1, ActiveFrom: "03-01", ActiveUntil: "11-30"},
+	}
+	results := notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	assert.NoError(t, results[0].Error)
+	assert.Empty(t, mockCh.calls)
+
+	notifier.Clock = func() time.Time { return monday.AddDate(0, 0, 56) }
+	results = notifier.Run(cfg)
+
+	require.Len(t, results, 1)
+	require.Len(t, mockCh.calls, 1)
+	assert.Equal(t, "Home: Expected Garden Waste collection tomorrow (Tuesday) but none scheduled.", mockCh.calls[0].msg.Body)
+}
+
+func TestPrintScrapers(t *testing.T) {
+	var buf bytes.Buffer
+	printScrapers(&buf, []scraper.Info{{
+		Name:             "bracknell",
+		DisplayName:      "Bracknell Forest Council",
+		PostcodePrefixes: []string{"RG12", "RG42"},
+		Fields:           []scraper.Field{{Name: "postcode"}, {Name: "address_code"}},
+	}})
+
+	assert.Equal(t, "NAME       COUNCIL                   POSTCODES  FIELDS\n"+
+		"bracknell  Bracknell Forest Council  RG12 RG42  postcode, address_code\n", buf.String())
+}
+
+func TestNotifier_ScrapeUsesLocationTimeout(t *testing.T) {
+	mock := &mockScraper{binTimes: []scraper.BinTime{}}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": mock}),
+		Channels:       []clients.NotificationChannel{&mockChannel{name: "sms"}},
+		Clock:          func() time.Time { return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) },
+	}
+
+	cfg := createTestConfig()
+	cfg.Locations[0].ScrapeTimeout = 90 * time.Second
+	start := time.Now()
+	notifier.Run(cfg)
+
+	require.NotNil(t, mock.ctx)
+	deadline, ok := mock.ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, start.Add(90*time.Second), deadline, time.Second)
+	assert.ErrorIs(t, mock.ctx.Err(), context.Canceled, "scrape context released after the scrape")
+}
+
+func TestNotifier_RunContextCancelsScrapes(t *testing.T) {
+	mockCh := &mockChannel{name: "sms"}
+	notifier := &Notifier{
+		ScraperFactory: newMockFactory(map[string]*mockScraper{"bracknell": {binTimes: []scraper.BinTime{}}}),
+		Channels:       []clients.NotificationChannel{mockCh},
+		Clock:          func() time.Time { return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := notifier.RunContext(ctx, createTestConfig())
+
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].Error, context.Canceled)
+	assert.Contains(t, results[0].Error.Error(), "[Home] scrape error")
+	assert.Empty(t, mockCh.calls)
 }
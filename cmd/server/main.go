@@ -11,15 +11,17 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/stebennett/bin-notifier/pkg/browser"
 	"github.com/stebennett/bin-notifier/pkg/cache"
 	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/dateutil"
 	"github.com/stebennett/bin-notifier/pkg/schedule"
 	"github.com/stebennett/bin-notifier/pkg/scraper"
 )
 
 // BinScraper is an interface for scraping bin collection times.
 type BinScraper interface {
-	ScrapeBinTimes(postcode string, address string) ([]scraper.BinTime, error)
+	ScrapeBinTimes(ctx context.Context, postcode string, address string) ([]scraper.BinTime, error)
 }
 
 // ScraperFactory resolves a BinScraper by name.
@@ -48,11 +50,16 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := scraper.RegisterRecipes(cfg.Recipes); err != nil {
+		log.Fatal(err)
+	}
 
+	// Chrome starts on the first scrape and is shared by every request.
+	pool := browser.New(cfg.Browser.MaxTabs)
 	app := &App{
 		cfg: cfg,
 		scraperFactory: func(name string) (BinScraper, error) {
-			return scraper.NewScraper(name)
+			return scraper.NewScraper(name, scraper.Options{Location: cfg.Zone, Browser: pool})
 		},
 		cache: cache.New(6 * time.Hour),
 		now:   time.Now,
@@ -68,7 +75,9 @@ func main() {
 	s.AddTool(getNextCollectionTool(), app.handleGetNextCollection)
 	s.AddTool(listLocationsTool(), app.handleListLocations)
 
-	if err := server.ServeStdio(s); err != nil {
+	err = server.ServeStdio(s)
+	pool.Close()
+	if err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
@@ -105,7 +114,7 @@ func getNextCollectionTool() mcp.Tool {
 
 func listLocationsTool() mcp.Tool {
 	return mcp.NewTool("list_locations",
-		mcp.WithDescription("List all configured locations with their scrapers and collection day schedules."),
+		mcp.WithDescription("List all configured locations with their scrapers and collection day schedules, and the council scrapers available."),
 	)
 }
 
@@ -123,8 +132,11 @@ type collectionEntry struct {
 }
 
 func (a *App) handleGetCollections(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	now := a.now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	zone := a.cfg.Zone
+	if zone == nil {
+		zone = time.UTC
+	}
+	today := dateutil.Today(a.now(), zone)
 
 	rangeParam := request.GetString("range", "")
 	dateParam := request.GetString("date", "")
@@ -136,7 +148,7 @@ func (a *App) handleGetCollections(_ context.Context, request mcp.CallToolReques
 	}
 
 	locations := filterLocations(a.cfg.Locations, locationFilter)
-	collections := schedule.ProjectCollections(locations, from, to)
+	collections := schedule.ProjectCollections(locations, from, to, zone, a.cfg.HolidayCalendar)
 
 	entries := make([]collectionEntry, len(collections))
 	for i, c := range collections {
@@ -165,7 +177,10 @@ type nextCollectionEntry struct {
 	Date     string   `json:"date"`
 }
 
-func (a *App) handleGetNextCollection(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// handleGetNextCollection scrapes each location not in the cache. Scrapes stop
+// when ctx is cancelled, e.g. because the client disconnected, or when the
+// location's scrape_timeout passes.
+func (a *App) handleGetNextCollection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	binTypeFilter := request.GetString("bin_type", "")
 	locationFilter := request.GetString("location", "")
 
@@ -175,19 +190,21 @@ func (a *App) handleGetNextCollection(_ context.Context, request mcp.CallToolReq
 	var errs []string
 
 	for _, loc := range locations {
-		binTimes, ok := a.cache.Get(loc.PostCode, loc.AddressCode)
+		binTimes, ok := a.cache.Get(loc.Scraper, loc.PostCode, loc.AddressCode)
 		if !ok {
 			s, err := a.scraperFactory(loc.Scraper)
 			if err != nil {
 				errs = append(errs, fmt.Sprintf("[%s] scraper error: %v", loc.Label, err))
 				continue
 			}
-			binTimes, err = s.ScrapeBinTimes(loc.PostCode, loc.AddressCode)
+			scrapeCtx, cancel := scraper.WithTimeout(ctx, loc.ScrapeTimeout)
+			binTimes, err = s.ScrapeBinTimes(scrapeCtx, loc.PostCode, loc.AddressCode)
+			cancel()
 			if err != nil {
 				errs = append(errs, fmt.Sprintf("[%s] scrape error: %v", loc.Label, err))
 				continue
 			}
-			a.cache.Set(loc.PostCode, loc.AddressCode, binTimes)
+			a.cache.Set(loc.Scraper, loc.PostCode, loc.AddressCode, binTimes)
 		}
 
 		for _, bt := range binTimes {
@@ -214,7 +231,8 @@ func (a *App) handleGetNextCollection(_ context.Context, request mcp.CallToolReq
 }
 
 type listLocationsResponse struct {
-	Locations []locationInfo `json:"locations"`
+	Locations         []locationInfo `json:"locations"`
+	AvailableScrapers []scraper.Info `json:"available_scrapers"`
 }
 
 type locationInfo struct {
@@ -225,10 +243,13 @@ type locationInfo struct {
 }
 
 type collectionDayInfo struct {
-	Day           string   `json:"day"`
+	Day           string   `json:"day,omitempty"`
 	Types         []string `json:"types"`
-	EveryNWeeks   int      `json:"every_n_weeks"`
+	EveryNWeeks   int      `json:"every_n_weeks,omitempty"`
 	ReferenceDate string   `json:"reference_date,omitempty"`
+	RRule         string   `json:"rrule,omitempty"`
+	ActiveFrom    string   `json:"active_from,omitempty"`
+	ActiveUntil   string   `json:"active_until,omitempty"`
 }
 
 func (a *App) handleListLocations(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -236,12 +257,18 @@ func (a *App) handleListLocations(_ context.Context, _ mcp.CallToolRequest) (*mc
 	for _, loc := range a.cfg.Locations {
 		var days []collectionDayInfo
 		for _, cd := range loc.CollectionDays {
-			days = append(days, collectionDayInfo{
-				Day:           cd.Day.String(),
+			info := collectionDayInfo{
 				Types:         cd.Types,
-				EveryNWeeks:   cd.EveryNWeeks,
 				ReferenceDate: cd.ReferenceDate,
-			})
+				RRule:         cd.RRule,
+				ActiveFrom:    cd.ActiveFrom,
+				ActiveUntil:   cd.ActiveUntil,
+			}
+			if cd.RRule == "" {
+				info.Day = cd.Day.String()
+				info.EveryNWeeks = cd.EveryNWeeks
+			}
+			days = append(days, info)
 		}
 		locs = append(locs, locationInfo{
 			Label:          loc.Label,
@@ -251,7 +278,7 @@ func (a *App) handleListLocations(_ context.Context, _ mcp.CallToolRequest) (*mc
 		})
 	}
 
-	resp := listLocationsResponse{Locations: locs}
+	resp := listLocationsResponse{Locations: locs, AvailableScrapers: scraper.List()}
 	return jsonResult(resp)
 }
 
@@ -284,7 +311,7 @@ func resolveDateRange(rangeParam, dateParam string, today time.Time) (time.Time,
 	}
 
 	if dateParam != "" {
-		d, err := time.Parse("2006-01-02", dateParam)
+		d, err := time.ParseInLocation("2006-01-02", dateParam, today.Location())
 		if err != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("invalid date: %q", dateParam)
 		}
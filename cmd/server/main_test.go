@@ -18,9 +18,15 @@ import (
 type mockScraper struct {
 	binTimes []scraper.BinTime
 	err      error
+	// ctx is the context of the last scrape.
+	ctx context.Context
 }
 
-func (m *mockScraper) ScrapeBinTimes(postcode string, address string) ([]scraper.BinTime, error) {
+func (m *mockScraper) ScrapeBinTimes(ctx context.Context, postcode string, address string) ([]scraper.BinTime, error) {
+	m.ctx = ctx
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return m.binTimes, m.err
 }
 
@@ -117,6 +123,25 @@ func TestGetCollections_Today(t *testing.T) {
 	assert.Equal(t, "2026-03-17", resp.Collections[0].Date)
 }
 
+func TestGetCollections_UsesConfiguredTimezone(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	// 00:30 BST on Tuesday 2026-06-16 is still Monday in UTC.
+	now := time.Date(2026, 6, 15, 23, 30, 0, 0, time.UTC)
+	app := testApp(testLocations(), nil, now)
+	app.cfg.Zone = london
+
+	result, err := app.handleGetCollections(context.Background(), callTool(map[string]any{"range": "today"}))
+	require.NoError(t, err)
+
+	var resp collectionsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &resp))
+
+	assert.Len(t, resp.Collections, 1)
+	assert.Equal(t, "2026-06-16", resp.Collections[0].Date)
+	assert.Equal(t, "Home", resp.Collections[0].Location)
+}
+
 func TestGetCollections_ThisWeek(t *testing.T) {
 	// Monday 2026-03-16
 	now := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
@@ -164,6 +189,23 @@ func TestGetCollections_LocationFilter(t *testing.T) {
 	assert.Equal(t, "Office", resp.Collections[0].Location)
 }
 
+func TestGetCollections_AppliesExceptions(t *testing.T) {
+	now := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
+	locations := testLocations()
+	locations[0].Exceptions = []config.Exception{{From: "2026-03-17", To: "2026-03-18"}}
+	app := testApp(locations, nil, now)
+
+	result, err := app.handleGetCollections(context.Background(), callTool(map[string]any{"range": "this_week", "location": "home"}))
+	require.NoError(t, err)
+
+	var resp collectionsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &resp))
+
+	assert.Len(t, resp.Collections, 1)
+	assert.Equal(t, "2026-03-18", resp.Collections[0].Date)
+	assert.Equal(t, []string{"Recycling", "General Waste"}, resp.Collections[0].Types)
+}
+
 func TestGetCollections_InvalidDate(t *testing.T) {
 	now := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
 	app := testApp(testLocations(), nil, now)
@@ -186,6 +228,27 @@ func TestGetCollections_NoResults(t *testing.T) {
 	assert.Empty(t, resp.Collections)
 }
 
+func TestGetCollections_RRule(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	locations := []config.Location{{
+		Label: "Home",
+		CollectionDays: []config.CollectionDay{
+			{RRule: "FREQ=MONTHLY;BYDAY=1MO", Types: []string{"Bulky Waste"}},
+		},
+	}}
+	app := testApp(locations, nil, now)
+
+	result, err := app.handleGetCollections(context.Background(), callTool(map[string]any{"range": "next_week"}))
+	require.NoError(t, err)
+
+	var resp collectionsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &resp))
+
+	assert.Len(t, resp.Collections, 1)
+	assert.Equal(t, "2026-03-02", resp.Collections[0].Date)
+	assert.Equal(t, []string{"Bulky Waste"}, resp.Collections[0].Types)
+}
+
 // --- get_next_collection tests ---
 
 func TestGetNextCollection_ReturnsScrapedData(t *testing.T) {
@@ -324,6 +387,28 @@ func TestGetNextCollection_ScraperError(t *testing.T) {
 
 // --- list_locations tests ---
 
+func TestGetNextCollection_ScrapeUsesContext(t *testing.T) {
+	now := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
+	mock := &mockScraper{binTimes: []scraper.BinTime{}}
+	locations := testLocations()[:1]
+	locations[0].ScrapeTimeout = 30 * time.Second
+	app := testApp(locations, map[string]*mockScraper{"bracknell": mock}, now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := app.handleGetNextCollection(ctx, callTool(map[string]any{}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "context canceled")
+
+	start := time.Now()
+	_, err = app.handleGetNextCollection(context.Background(), callTool(map[string]any{}))
+	require.NoError(t, err)
+	deadline, ok := mock.ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, start.Add(30*time.Second), deadline, time.Second)
+}
+
 func TestListLocations(t *testing.T) {
 	now := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
 	app := testApp(testLocations(), nil, now)
@@ -346,6 +431,35 @@ func TestListLocations(t *testing.T) {
 
 	assert.Equal(t, "Office", resp.Locations[1].Label)
 	assert.Equal(t, "wokingham", resp.Locations[1].Scraper)
+
+	require.Len(t, resp.AvailableScrapers, 3)
+	assert.Equal(t, "bracknell", resp.AvailableScrapers[0].Name)
+	assert.Equal(t, "Bracknell Forest Council", resp.AvailableScrapers[0].DisplayName)
+	assert.Contains(t, resp.AvailableScrapers[0].PostcodePrefixes, "RG12")
+	assert.Equal(t, "wokingham", resp.AvailableScrapers[1].Name)
+	assert.Equal(t, "wokingham-chrome", resp.AvailableScrapers[2].Name)
+}
+
+func TestListLocations_RRule(t *testing.T) {
+	now := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
+	locations := []config.Location{{
+		Label: "Home",
+		CollectionDays: []config.CollectionDay{
+			{RRule: "FREQ=MONTHLY;BYDAY=1MO", Types: []string{"Bulky Waste"}, ActiveUntil: "2026-12-31"},
+		},
+	}}
+	app := testApp(locations, nil, now)
+
+	result, err := app.handleListLocations(context.Background(), callTool(map[string]any{}))
+	require.NoError(t, err)
+
+	var resp listLocationsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &resp))
+
+	require.Len(t, resp.Locations[0].CollectionDays, 1)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=1MO", resp.Locations[0].CollectionDays[0].RRule)
+	assert.Empty(t, resp.Locations[0].CollectionDays[0].Day)
+	assert.Equal(t, "2026-12-31", resp.Locations[0].CollectionDays[0].ActiveUntil)
 }
 
 // --- resolveDateRange tests ---
@@ -0,0 +1,164 @@
+// Package browser shares one headless Chrome between chromedp-based scrapers.
+package browser
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ErrClosed is returned by Tab after the pool has been closed.
+var ErrClosed = errors.New("browser pool is closed")
+
+// Pool owns a single headless Chrome, started on first use, and hands out tabs
+// in it, at most MaxTabs at a time.
+type Pool struct {
+	sem chan struct{}
+
+	mu            sync.Mutex
+	closed        bool
+	dir           string
+	cancelAlloc   context.CancelFunc
+	browserCtx    context.Context
+	cancelBrowser context.CancelFunc
+
+	// newTab opens a tab; tests replace it to avoid starting Chrome.
+	newTab func() (context.Context, context.CancelFunc, error)
+}
+
+// New creates a pool allowing maxTabs tabs open at once; values below 1 allow one.
+func New(maxTabs int) *Pool {
+	if maxTabs < 1 {
+		maxTabs = 1
+	}
+	p := &Pool{sem: make(chan struct{}, maxTabs)}
+	p.newTab = p.chromeTab
+	return p
+}
+
+// Tab waits for a free slot and opens a new tab. The returned context runs
+// chromedp actions in the tab and is cancelled when ctx is done, taking ctx's
+// deadline. Call release once finished with the tab to close it and free the
+// slot.
+func (p *Pool) Tab(ctx context.Context) (tabCtx context.Context, release func(), err error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		<-p.sem
+		return nil, nil, ErrClosed
+	}
+
+	tabCtx, cancelTab, err := p.newTab()
+	if err != nil {
+		<-p.sem
+		return nil, nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancelDeadline context.CancelFunc
+		tabCtx, cancelDeadline = context.WithDeadline(tabCtx, deadline)
+		cancelTab = join(cancelDeadline, cancelTab)
+	}
+	stop := context.AfterFunc(ctx, cancelTab)
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			stop()
+			cancelTab()
+			<-p.sem
+		})
+	}
+	return tabCtx, release, nil
+}
+
+// chromeTab opens a tab in the pool's browser, starting Chrome if it isn't
+// running or has exited.
+func (p *Pool) chromeTab() (context.Context, context.CancelFunc, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, nil, ErrClosed
+	}
+	if p.browserCtx == nil || p.browserCtx.Err() != nil {
+		if err := p.start(); err != nil {
+			return nil, nil, err
+		}
+	}
+	tabCtx, cancel := chromedp.NewContext(p.browserCtx)
+	return tabCtx, cancel, nil
+}
+
+// start launches Chrome with a fresh user data dir. p.mu must be held.
+func (p *Pool) start() error {
+	p.stop()
+
+	log.Printf("starting headless chrome")
+	dir, err := os.MkdirTemp("", "bin-notifier-chrome")
+	if err != nil {
+		return err
+	}
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.DisableGPU,
+		chromedp.UserDataDir(dir),
+		chromedp.Flag("headless", true),
+		chromedp.NoSandbox,
+	)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+
+	// Running no actions starts the browser, so tabs can be opened in it.
+	if err := chromedp.Run(browserCtx); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		os.RemoveAll(dir)
+		return err
+	}
+
+	p.dir = dir
+	p.cancelAlloc = cancelAlloc
+	p.browserCtx = browserCtx
+	p.cancelBrowser = cancelBrowser
+	return nil
+}
+
+// stop shuts Chrome down and removes its user data dir. p.mu must be held.
+func (p *Pool) stop() {
+	if p.browserCtx == nil {
+		return
+	}
+	p.cancelBrowser()
+	p.cancelAlloc()
+	os.RemoveAll(p.dir)
+	p.browserCtx = nil
+}
+
+// Close shuts Chrome down. Tabs still open are closed with it, and later calls
+// to Tab fail with ErrClosed.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	p.stop()
+	return nil
+}
+
+func join(cancels ...context.CancelFunc) context.CancelFunc {
+	return func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePool returns a pool whose tabs are plain contexts, counting open tabs.
+func fakePool(maxTabs int) (*Pool, *atomic.Int32) {
+	var open atomic.Int32
+	p := New(maxTabs)
+	p.newTab = func() (context.Context, context.CancelFunc, error) {
+		open.Add(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		return ctx, func() {
+			if ctx.Err() == nil {
+				open.Add(-1)
+			}
+			cancel()
+		}, nil
+	}
+	return p, &open
+}
+
+func TestPool_LimitsOpenTabs(t *testing.T) {
+	p, open := fakePool(2)
+
+	_, release1, err := p.Tab(context.Background())
+	require.NoError(t, err)
+	_, release2, err := p.Tab(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), open.Load())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err = p.Tab(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "third tab waits for a free slot")
+
+	release1()
+	release1()
+	assert.Equal(t, int32(1), open.Load())
+	_, release3, err := p.Tab(context.Background())
+	require.NoError(t, err)
+
+	release2()
+	release3()
+	assert.Equal(t, int32(0), open.Load())
+}
+
+func TestPool_TabFollowsContext(t *testing.T) {
+	p, open := fakePool(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tabCtx, release, err := p.Tab(ctx)
+	require.NoError(t, err)
+	defer release()
+
+	cancel()
+	assert.Eventually(t, func() bool { return tabCtx.Err() != nil }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(0), open.Load())
+
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel = context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	release()
+	tabCtx, release, err = p.Tab(ctx)
+	require.NoError(t, err)
+	defer release()
+	tabDeadline, ok := tabCtx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, tabDeadline)
+}
+
+func TestPool_TabErrorFreesSlot(t *testing.T) {
+	p := New(1)
+	p.newTab = func() (context.Context, context.CancelFunc, error) {
+		return nil, nil, errors.New("chrome not found")
+	}
+
+	_, _, err := p.Tab(context.Background())
+	assert.EqualError(t, err, "chrome not found")
+	_, _, err = p.Tab(context.Background())
+	assert.EqualError(t, err, "chrome not found", "slot was released")
+}
+
+func TestPool_Close(t *testing.T) {
+	p, _ := fakePool(1)
+
+	assert.NoError(t, p.Close())
+	_, _, err := p.Tab(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+
+	// Closing a pool that never started Chrome opens no browser.
+	p = New(1)
+	assert.NoError(t, p.Close())
+	_, _, err = p.Tab(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestNew_AllowsAtLeastOneTab(t *testing.T) {
+	assert.Equal(t, 1, cap(New(0).sem))
+	assert.Equal(t, 3, cap(New(3).sem))
+}
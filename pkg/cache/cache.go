@@ -29,16 +29,18 @@ func New(ttl time.Duration) *ScraperCache {
 	}
 }
 
-func cacheKey(postcode, addressCode string) string {
-	return postcode + "|" + addressCode
+// cacheKey includes the scraper because two councils can be asked about the
+// same postcode and address code.
+func cacheKey(scraperName, postcode, addressCode string) string {
+	return scraperName + "|" + postcode + "|" + addressCode
 }
 
 // Get returns cached bin times if present and not expired.
-func (c *ScraperCache) Get(postcode, addressCode string) ([]scraper.BinTime, bool) {
+func (c *ScraperCache) Get(scraperName, postcode, addressCode string) ([]scraper.BinTime, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	key := cacheKey(postcode, addressCode)
+	key := cacheKey(scraperName, postcode, addressCode)
 	entry, ok := c.entries[key]
 	if !ok {
 		return nil, false
@@ -50,11 +52,11 @@ func (c *ScraperCache) Get(postcode, addressCode string) ([]scraper.BinTime, boo
 }
 
 // Set stores bin times in the cache.
-func (c *ScraperCache) Set(postcode, addressCode string, binTimes []scraper.BinTime) {
+func (c *ScraperCache) Set(scraperName, postcode, addressCode string, binTimes []scraper.BinTime) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := cacheKey(postcode, addressCode)
+	key := cacheKey(scraperName, postcode, addressCode)
 	c.entries[key] = cacheEntry{
 		binTimes:  binTimes,
 		expiresAt: c.now().Add(c.ttl),
@@ -62,8 +64,8 @@ func (c *ScraperCache) Set(postcode, addressCode string, binTimes []scraper.BinT
 }
 
 // Invalidate removes a specific cache entry.
-func (c *ScraperCache) Invalidate(postcode, addressCode string) {
+func (c *ScraperCache) Invalidate(scraperName, postcode, addressCode string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.entries, cacheKey(postcode, addressCode))
+	delete(c.entries, cacheKey(scraperName, postcode, addressCode))
 }
@@ -16,17 +16,31 @@ func TestSetAndGet(t *testing.T) {
 		{Type: "Recycling", CollectionTime: time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)},
 	}
 
-	c.Set("RG12 1AB", "12345", bins)
+	c.Set("bracknell", "RG12 1AB", "12345", bins)
 
-	got, ok := c.Get("RG12 1AB", "12345")
+	got, ok := c.Get("bracknell", "RG12 1AB", "12345")
 	assert.True(t, ok)
 	assert.Equal(t, bins, got)
 }
 
+func TestKeyIncludesScraper(t *testing.T) {
+	c := New(6 * time.Hour)
+
+	bins := []scraper.BinTime{
+		{Type: "Recycling", CollectionTime: time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)},
+	}
+
+	c.Set("bracknell", "RG12 1AB", "12345", bins)
+
+	got, ok := c.Get("wokingham", "RG12 1AB", "12345")
+	assert.False(t, ok)
+	assert.Nil(t, got)
+}
+
 func TestGetMissing(t *testing.T) {
 	c := New(6 * time.Hour)
 
-	got, ok := c.Get("RG12 1AB", "12345")
+	got, ok := c.Get("bracknell", "RG12 1AB", "12345")
 	assert.False(t, ok)
 	assert.Nil(t, got)
 }
@@ -40,16 +54,16 @@ func TestExpiry(t *testing.T) {
 		{Type: "Recycling", CollectionTime: time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)},
 	}
 
-	c.Set("RG12 1AB", "12345", bins)
+	c.Set("bracknell", "RG12 1AB", "12345", bins)
 
 	// Still valid
-	got, ok := c.Get("RG12 1AB", "12345")
+	got, ok := c.Get("bracknell", "RG12 1AB", "12345")
 	assert.True(t, ok)
 	assert.Equal(t, bins, got)
 
 	// Advance past TTL
 	c.now = func() time.Time { return now.Add(2 * time.Hour) }
-	got, ok = c.Get("RG12 1AB", "12345")
+	got, ok = c.Get("bracknell", "RG12 1AB", "12345")
 	assert.False(t, ok)
 	assert.Nil(t, got)
 }
@@ -61,10 +75,10 @@ func TestInvalidate(t *testing.T) {
 		{Type: "Recycling", CollectionTime: time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)},
 	}
 
-	c.Set("RG12 1AB", "12345", bins)
-	c.Invalidate("RG12 1AB", "12345")
+	c.Set("bracknell", "RG12 1AB", "12345", bins)
+	c.Invalidate("bracknell", "RG12 1AB", "12345")
 
-	got, ok := c.Get("RG12 1AB", "12345")
+	got, ok := c.Get("bracknell", "RG12 1AB", "12345")
 	assert.False(t, ok)
 	assert.Nil(t, got)
 }
@@ -81,16 +95,16 @@ func TestConcurrentAccess(t *testing.T) {
 		wg.Add(2)
 		go func() {
 			defer wg.Done()
-			c.Set("RG12 1AB", "12345", bins)
+			c.Set("bracknell", "RG12 1AB", "12345", bins)
 		}()
 		go func() {
 			defer wg.Done()
-			c.Get("RG12 1AB", "12345")
+			c.Get("bracknell", "RG12 1AB", "12345")
 		}()
 	}
 	wg.Wait()
 
-	got, ok := c.Get("RG12 1AB", "12345")
+	got, ok := c.Get("bracknell", "RG12 1AB", "12345")
 	assert.True(t, ok)
 	assert.Equal(t, bins, got)
 }
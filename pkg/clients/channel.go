@@ -0,0 +1,184 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+)
+
+// MessageKind distinguishes scraped collections, schedule warnings and bring-in
+// reminders.
+type MessageKind string
+
+const (
+	// KindCollection is a collection confirmed by the council website.
+	KindCollection MessageKind = "collection"
+	// KindScheduleWarning is a configured collection day with nothing scheduled.
+	KindScheduleWarning MessageKind = "schedule_warning"
+	// KindBringIn reminds the recipient to bring the bins back in after a collection.
+	KindBringIn MessageKind = "bring_in"
+	// KindDigest combines the messages for several locations into one.
+	KindDigest MessageKind = "digest"
+)
+
+// Message is a structured bin collection notification delivered by a NotificationChannel.
+// Date is the collection day the message is about and When describes it relative
+// to the run, e.g. "tomorrow" or "in 2 days". Source records whether Types came
+// from the council website or the configured schedule. To, when set, replaces
+// the channel's configured recipients for this message.
+type Message struct {
+	Kind     MessageKind
+	Title    string
+	Body     string
+	Location string
+	PostCode string
+	Date     time.Time
+	When     string
+	Types    []string
+	Source   string
+	To       []string
+}
+
+// NotificationChannel delivers messages to a single notification service.
+type NotificationChannel interface {
+	// Name returns the configured name of the channel, used in results and logs.
+	Name() string
+	// Send delivers the message. In dry-run mode it logs the message instead of sending it.
+	Send(msg Message, dryRun bool) error
+}
+
+// Router is implemented by channels whose delivery of a message depends on its
+// location, such as per-location chats or card colours. Messages with different
+// routes must not be combined into one digest.
+type Router interface {
+	// Route returns the location whose settings apply to msg, or "" when the
+	// channel's defaults do.
+	Route(msg Message) string
+}
+
+// NewChannel creates a NotificationChannel from its config entry.
+func NewChannel(cc config.ChannelConfig, cfg config.Config) (NotificationChannel, error) {
+	switch strings.ToLower(cc.Type) {
+	case "twilio":
+		return NewTwilioChannel(cc.Name, NewTwilioClient(), cfg.FromNumber, cfg.ToNumber), nil
+	case "email":
+		return NewEmailChannel(cc.Name, cc.Email), nil
+	case "ntfy":
+		return NewNtfyChannel(cc.Name, cc.Push), nil
+	case "gotify":
+		return NewGotifyChannel(cc.Name, cc.Push), nil
+	case "telegram":
+		routes := make(map[string][]string)
+		for _, loc := range cfg.Locations {
+			if len(loc.TelegramChatIDs) > 0 {
+				routes[loc.Label] = loc.TelegramChatIDs
+			}
+		}
+		return NewTelegramChannel(cc.Name, cc.Telegram, routes), nil
+	case "webhook":
+		return NewWebhookChannel(cc.Name, cc.Webhook)
+	case "slack":
+		return NewSlackChannel(cc.Name, cc.Slack, locationColors(cfg)), nil
+	case "discord":
+		return NewDiscordChannel(cc.Name, cc.Discord, locationColors(cfg)), nil
+	default:
+		return nil, fmt.Errorf("unknown channel: %q", cc.Type)
+	}
+}
+
+// locationColors maps location labels to their configured card colours.
+func locationColors(cfg config.Config) map[string]string {
+	colors := make(map[string]string)
+	for _, loc := range cfg.Locations {
+		if loc.Color != "" {
+			colors[loc.Label] = loc.Color
+		}
+	}
+	return colors
+}
+
+// colorRoute returns the location whose colour applies to msg, or "" for the
+// default colours.
+func colorRoute(msg Message, colors map[string]string) string {
+	if _, ok := colors[msg.Location]; ok {
+		return msg.Location
+	}
+	return ""
+}
+
+// cardColor returns the colour for a message's card: the location's colour if set,
+// otherwise green for collections and amber for schedule warnings.
+func cardColor(msg Message, colors map[string]string) string {
+	if c, ok := colors[msg.Location]; ok {
+		return c
+	}
+	if msg.Kind == KindScheduleWarning {
+		return "#FFA000"
+	}
+	return "#2E7D32"
+}
+
+// cardSummary describes a message for rich card formats, built from its
+// structured fields rather than the flattened body (except for digests).
+func cardSummary(msg Message) string {
+	if msg.Kind == KindDigest {
+		// A digest body already lists each location's message.
+		return msg.Body
+	}
+	day := "tomorrow"
+	if !msg.Date.IsZero() {
+		day = msg.Date.Format("Monday 2 January")
+	}
+	switch msg.Kind {
+	case KindScheduleWarning:
+		return fmt.Sprintf("Expected collection on %s but none is scheduled.", day)
+	case KindBringIn:
+		return fmt.Sprintf("Bins to bring in after collection on %s.", day)
+	}
+	return fmt.Sprintf("Bins to put out for collection on %s.", day)
+}
+
+// postJSON posts payload as JSON to endpoint and treats any non-2xx status as an error.
+// Incoming-webhook URLs embed a secret, so transport errors are reported without the URL.
+func postJSON(client *http.Client, service string, endpoint string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s request failed: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return statusError(service, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// NewChannels creates all notification channels configured in cfg.
+func NewChannels(cfg config.Config) ([]NotificationChannel, error) {
+	channels := make([]NotificationChannel, 0, len(cfg.Channels))
+	for _, cc := range cfg.Channels {
+		ch, err := NewChannel(cc, cfg)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+	return channels, nil
+}
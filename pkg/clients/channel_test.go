@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChannel_Twilio(t *testing.T) {
+	cfg := config.Config{FromNumber: "+1234567890", ToNumber: "+0987654321"}
+
+	ch, err := NewChannel(config.ChannelConfig{Type: "twilio", Name: "sms"}, cfg)
+
+	require.NoError(t, err)
+	assert.IsType(t, &TwilioChannel{}, ch)
+	assert.Equal(t, "sms", ch.Name())
+}
+
+func TestNewChannel_UnknownType(t *testing.T) {
+	_, err := NewChannel(config.ChannelConfig{Type: "pigeon"}, config.Config{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown channel")
+}
+
+func TestNewChannels_CreatesAllConfigured(t *testing.T) {
+	cfg := config.Config{
+		FromNumber: "+1234567890",
+		ToNumber:   "+0987654321",
+		Channels: []config.ChannelConfig{
+			{Type: "twilio", Name: "sms"},
+			{Type: "twilio", Name: "sms-backup"},
+		},
+	}
+
+	channels, err := NewChannels(cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, channels, 2)
+	assert.Equal(t, "sms", channels[0].Name())
+	assert.Equal(t, "sms-backup", channels[1].Name())
+}
+
+func TestNewChannel_TelegramRoutesFromLocations(t *testing.T) {
+	cfg := config.Config{
+		Locations: []config.Location{
+			{Label: "Home"},
+			{Label: "Office", TelegramChatIDs: []string{"office"}},
+		},
+	}
+
+	ch, err := NewChannel(config.ChannelConfig{Type: "telegram", Telegram: config.TelegramConfig{ChatIDs: []string{"family"}}}, cfg)
+
+	require.NoError(t, err)
+	telegram := ch.(*TelegramChannel)
+	assert.Equal(t, []string{"family"}, telegram.chatIDs(Message{Location: "Home"}))
+	assert.Equal(t, []string{"office"}, telegram.chatIDs(Message{Location: "Office"}))
+}
+
+func TestNewChannel_CardChannelsUseLocationColors(t *testing.T) {
+	cfg := config.Config{
+		Locations: []config.Location{
+			{Label: "Home", Color: "#1E88E5"},
+			{Label: "Office"},
+		},
+	}
+
+	slack, err := NewChannel(config.ChannelConfig{Type: "slack", Name: "slack"}, cfg)
+	require.NoError(t, err)
+	discord, err := NewChannel(config.ChannelConfig{Type: "discord", Name: "discord"}, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"Home": "#1E88E5"}, slack.(*SlackChannel).colors)
+	assert.Equal(t, map[string]string{"Home": "#1E88E5"}, discord.(*DiscordChannel).colors)
+}
+
+func TestCardSummary(t *testing.T) {
+	date := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "Bins to put out for collection on Friday 16 January.",
+		cardSummary(Message{Kind: KindCollection, Date: date}))
+	assert.Equal(t, "Expected collection on Friday 16 January but none is scheduled.",
+		cardSummary(Message{Kind: KindScheduleWarning, Date: date}))
+	assert.Equal(t, "Bins to bring in after collection on Friday 16 January.",
+		cardSummary(Message{Kind: KindBringIn, Date: date}))
+	assert.Equal(t, "Home: Recycling\nOffice: Food Waste",
+		cardSummary(Message{Kind: KindDigest, Date: date, Body: "Home: Recycling\nOffice: Food Waste"}))
+}
@@ -0,0 +1,104 @@
+package clients
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+)
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordPayload struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+// discordMaxFields is the maximum number of fields Discord allows in an embed.
+const discordMaxFields = 25
+
+// DiscordChannel posts embeds to a Discord incoming webhook.
+type DiscordChannel struct {
+	name   string
+	cfg    config.ChatConfig
+	colors map[string]string
+	client *http.Client
+}
+
+// NewDiscordChannel creates a DiscordChannel. colors maps location labels to embed colours.
+func NewDiscordChannel(name string, cfg config.ChatConfig, colors map[string]string) *DiscordChannel {
+	if name == "" {
+		name = "discord"
+	}
+	return &DiscordChannel{
+		name:   name,
+		cfg:    cfg,
+		colors: colors,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *DiscordChannel) Name() string {
+	return c.name
+}
+
+// Route returns the location whose colour msg's embed uses, or "" for the defaults.
+func (c *DiscordChannel) Route(msg Message) string {
+	return colorRoute(msg, c.colors)
+}
+
+func (c *DiscordChannel) Send(msg Message, dryRun bool) error {
+	payload := c.buildPayload(msg)
+	if dryRun {
+		log.Printf("DRY RUN: Would have sent Discord embed with title: %s", msg.Title)
+		return nil
+	}
+	return postJSON(c.client, "discord", c.cfg.WebhookURL, payload)
+}
+
+func (c *DiscordChannel) buildPayload(msg Message) discordPayload {
+	value := "Tomorrow"
+	if !msg.Date.IsZero() {
+		value = msg.Date.Format("Mon 2 Jan")
+	}
+	if msg.Kind == KindScheduleWarning {
+		value = "Not scheduled"
+	}
+
+	var fields []discordField
+	for _, t := range msg.Types {
+		fields = append(fields, discordField{Name: t, Value: value, Inline: true})
+	}
+
+	// Discord expects the colour as an integer; cardColor always returns #RRGGBB.
+	color, _ := strconv.ParseInt(strings.TrimPrefix(cardColor(msg, c.colors), "#"), 16, 32)
+
+	// Fields beyond the limit continue in further embeds of the same colour.
+	embed := discordEmbed{Title: msg.Title, Description: cardSummary(msg), Color: int(color)}
+	var embeds []discordEmbed
+	for {
+		n := min(len(fields), discordMaxFields)
+		embed.Fields = fields[:n]
+		embeds = append(embeds, embed)
+		fields = fields[n:]
+		if len(fields) == 0 {
+			break
+		}
+		embed = discordEmbed{Color: int(color)}
+	}
+
+	return discordPayload{Embeds: embeds}
+}
@@ -0,0 +1,81 @@
+package clients
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordChannel_PostsEmbed(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusNoContent)
+	channel := NewDiscordChannel("", config.ChatConfig{WebhookURL: server.URL + "/api/webhooks/1/abc"}, map[string]string{"Home": "#1E88E5"})
+
+	err := channel.Send(testCardMessage(KindCollection), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "discord", channel.Name())
+	require.Len(t, *requests, 1)
+	assert.JSONEq(t, `{
+		"embeds": [{
+			"title": "Home: bin collection tomorrow",
+			"description": "Bins to put out for collection on Friday 16 January.",
+			"color": 2001125,
+			"fields": [
+				{"name": "Recycling", "value": "Fri 16 Jan", "inline": true},
+				{"name": "Garden Waste", "value": "Fri 16 Jan", "inline": true}
+			]
+		}]
+	}`, (*requests)[0].body)
+}
+
+func TestDiscordChannel_WarningEmbed(t *testing.T) {
+	channel := NewDiscordChannel("discord", config.ChatConfig{}, nil)
+
+	payload := channel.buildPayload(testCardMessage(KindScheduleWarning))
+
+	embed := payload.Embeds[0]
+	assert.Equal(t, 0xFFA000, embed.Color)
+	assert.Equal(t, "Not scheduled", embed.Fields[0].Value)
+	assert.Equal(t, "Expected collection on Friday 16 January but none is scheduled.", embed.Description)
+}
+
+func TestDiscordChannel_SplitsFieldsAcrossEmbeds(t *testing.T) {
+	channel := NewDiscordChannel("discord", config.ChatConfig{}, nil)
+	msg := testCardMessage(KindCollection)
+	msg.Types = make([]string, 27)
+	for i := range msg.Types {
+		msg.Types[i] = "Bin"
+	}
+
+	payload := channel.buildPayload(msg)
+
+	require.Len(t, payload.Embeds, 2)
+	assert.Equal(t, msg.Title, payload.Embeds[0].Title)
+	assert.Len(t, payload.Embeds[0].Fields, 25)
+	assert.Empty(t, payload.Embeds[1].Title)
+	assert.Len(t, payload.Embeds[1].Fields, 2)
+	assert.Equal(t, payload.Embeds[0].Color, payload.Embeds[1].Color)
+}
+
+func TestDiscordChannel_Non2xxReturnsError(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusBadRequest)
+	channel := NewDiscordChannel("discord", config.ChatConfig{WebhookURL: server.URL}, nil)
+
+	err := channel.Send(testCardMessage(KindCollection), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "discord returned status 400")
+}
+
+func TestDiscordChannel_DryRunDoesNotPost(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewDiscordChannel("discord", config.ChatConfig{WebhookURL: server.URL}, nil)
+
+	err := channel.Send(testCardMessage(KindCollection), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 0)
+}
@@ -0,0 +1,183 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+)
+
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<html>
+<body>
+<h2>{{.Title}}</h2>
+<p>{{.Body}}</p>
+{{- if .Types}}
+<h3>{{.Location}}</h3>
+<ul>
+{{- range .Types}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// EmailChannel sends messages as multipart plain-text and HTML email over SMTP.
+type EmailChannel struct {
+	name      string
+	cfg       config.EmailConfig
+	tlsConfig *tls.Config
+	now       func() time.Time
+}
+
+// NewEmailChannel creates an EmailChannel using the given SMTP settings.
+func NewEmailChannel(name string, cfg config.EmailConfig) *EmailChannel {
+	if name == "" {
+		name = "email"
+	}
+	return &EmailChannel{
+		name:      name,
+		cfg:       cfg,
+		tlsConfig: &tls.Config{ServerName: cfg.Host},
+		now:       time.Now,
+	}
+}
+
+func (c *EmailChannel) Name() string {
+	return c.name
+}
+
+func (c *EmailChannel) Send(msg Message, dryRun bool) error {
+	to := c.cfg.To
+	if len(msg.To) > 0 {
+		to = msg.To
+	}
+
+	if dryRun {
+		log.Printf("DRY RUN: Would have sent email from %s to %s with subject: %s", c.cfg.From, strings.Join(to, ", "), msg.Title)
+		return nil
+	}
+
+	data, err := c.buildMessage(msg, to)
+	if err != nil {
+		return err
+	}
+	return classifySMTPError(c.deliver(to, data))
+}
+
+func (c *EmailChannel) buildMessage(msg Message, to []string) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	text := msg.Body
+	if len(msg.Types) > 0 {
+		text += "\n\n" + msg.Location + ":\n"
+		for _, t := range msg.Types {
+			text += "- " + t + "\n"
+		}
+	}
+	if err := writeQuotedPrintablePart(mw, "text/plain; charset=UTF-8", []byte(text)); err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if err := emailHTMLTemplate.Execute(&html, msg); err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintablePart(mw, "text/html; charset=UTF-8", html.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", c.cfg.From)
+	fmt.Fprintf(&out, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&out, "Date: %s\r\n", c.now().Format(time.RFC1123Z))
+	fmt.Fprintf(&out, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&out, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(mw *multipart.Writer, contentType string, content []byte) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write(content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func (c *EmailChannel) deliver(to []string, data []byte) error {
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+
+	var conn net.Conn
+	var err error
+	if c.cfg.TLS == "implicit" {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, c.tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, 30*time.Second)
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, c.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if c.cfg.TLS == "starttls" {
+		if err := client.StartTLS(c.tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if c.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if err := client.Mail(c.cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
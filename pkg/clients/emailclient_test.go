@@ -0,0 +1,240 @@
+package clients
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a minimal in-process SMTP server that records received mail.
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	from     string
+	rcpts    []string
+	data     string
+	auth     string
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTPServer{listener: l, done: make(chan struct{})}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer close(s.done)
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP fake")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-localhost")
+			tp.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+			s.mu.Unlock()
+			tp.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = line
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, line)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(data)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func testEmailConfig(port int) config.EmailConfig {
+	return config.EmailConfig{
+		Host: "localhost",
+		Port: port,
+		TLS:  "none",
+		From: "bins@example.com",
+		To:   []string{"alice@example.com", "bob@example.com"},
+	}
+}
+
+func testEmailMessage() Message {
+	return Message{
+		Title:    "Home: bin collection tomorrow",
+		Body:     "Home: Tomorrows bin collections are: Recycling, Garden Waste",
+		Location: "Home",
+		Types:    []string{"Recycling", "Garden Waste"},
+	}
+}
+
+func TestEmailChannel_SendsMultipartMessage(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	channel := NewEmailChannel("", testEmailConfig(server.port()))
+
+	err := channel.Send(testEmailMessage(), false)
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Equal(t, "email", channel.Name())
+	assert.Equal(t, "MAIL FROM:<bins@example.com>", server.from)
+	assert.Equal(t, []string{"RCPT TO:<alice@example.com>", "RCPT TO:<bob@example.com>"}, server.rcpts)
+
+	m, err := mail.ReadMessage(strings.NewReader(server.data))
+	require.NoError(t, err)
+	assert.Equal(t, "Home: bin collection tomorrow", m.Header.Get("Subject"))
+	assert.Equal(t, "alice@example.com, bob@example.com", m.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	parts := map[string]string{}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(p)
+		require.NoError(t, err)
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+
+	assert.Contains(t, parts["text/plain"], "Home: Tomorrows bin collections are: Recycling, Garden Waste")
+	assert.Contains(t, parts["text/plain"], "- Garden Waste")
+	assert.Contains(t, parts["text/html"], "<li>Recycling</li>")
+	assert.Contains(t, parts["text/html"], "<li>Garden Waste</li>")
+}
+
+func TestEmailChannel_MessageRecipientsOverrideConfig(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	channel := NewEmailChannel("email", testEmailConfig(server.port()))
+
+	msg := testEmailMessage()
+	msg.To = []string{"carol@example.com"}
+	err := channel.Send(msg, false)
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Equal(t, []string{"RCPT TO:<carol@example.com>"}, server.rcpts)
+	m, err := mail.ReadMessage(strings.NewReader(server.data))
+	require.NoError(t, err)
+	assert.Equal(t, "carol@example.com", m.Header.Get("To"))
+}
+
+func TestEmailChannel_AuthenticatesWhenUsernameSet(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	cfg := testEmailConfig(server.port())
+	cfg.Username = "user"
+	cfg.Password = "secret"
+	channel := NewEmailChannel("email", cfg)
+
+	err := channel.Send(testEmailMessage(), false)
+	require.NoError(t, err)
+	<-server.done
+
+	decoded, err := base64.StdEncoding.DecodeString(server.auth)
+	require.NoError(t, err)
+	assert.Equal(t, "\x00user\x00secret", string(decoded))
+}
+
+func TestEmailChannel_DryRunDoesNotConnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	channel := NewEmailChannel("email", testEmailConfig(port))
+
+	err = channel.Send(testEmailMessage(), true)
+	assert.NoError(t, err)
+}
+
+func TestEmailChannel_ReturnsConnectionError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	channel := NewEmailChannel("email", testEmailConfig(port))
+
+	err = channel.Send(testEmailMessage(), false)
+	assert.Error(t, err)
+}
+
+func TestEmailChannel_StartTLSRequiredByDefault(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	cfg := testEmailConfig(server.port())
+	cfg.TLS = "starttls"
+	channel := NewEmailChannel("email", cfg)
+
+	err := channel.Send(testEmailMessage(), false)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "starttls")
+}
+
+func TestEmailChannel_BuildMessageHeaders(t *testing.T) {
+	channel := NewEmailChannel("email", testEmailConfig(25))
+
+	data, err := channel.buildMessage(testEmailMessage(), []string{"alice@example.com"})
+	require.NoError(t, err)
+
+	m, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+	require.NoError(t, err)
+	assert.Equal(t, "1.0", m.Header.Get("MIME-Version"))
+	assert.Equal(t, "bins@example.com", m.Header.Get("From"))
+	assert.NotEmpty(t, m.Header.Get("Date"))
+	assert.Contains(t, string(data), "Content-Type: text/plain; charset=UTF-8")
+	assert.Contains(t, string(data), "Content-Type: text/html; charset=UTF-8")
+}
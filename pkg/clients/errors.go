@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	twilioClient "github.com/twilio/twilio-go/client"
+)
+
+// PermanentError marks a send failure that retrying cannot fix, such as an
+// invalid phone number or a rejected webhook URL.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err as a PermanentError. A nil err stays nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether retrying err is pointless. An error joining several
+// failures is only permanent if every one of them is.
+func IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		for _, e := range errs {
+			if !IsPermanent(e) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	}
+	var perm *PermanentError
+	return errors.As(err, &perm)
+}
+
+// RecipientError reports a failure to deliver to one recipient of a message, so
+// a retry can be limited to the recipients that failed.
+type RecipientError struct {
+	Recipient string
+	Err       error
+}
+
+func (e *RecipientError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Recipient, e.Err)
+}
+
+func (e *RecipientError) Unwrap() error {
+	return e.Err
+}
+
+// FailedRecipients returns the recipients named by RecipientErrors in err. It
+// returns nil if any failure is not tied to a recipient, meaning the whole
+// message failed.
+func FailedRecipients(err error) []string {
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var recipients []string
+	for _, e := range errs {
+		var rerr *RecipientError
+		if !errors.As(e, &rerr) {
+			return nil
+		}
+		recipients = append(recipients, rerr.Recipient)
+	}
+	return recipients
+}
+
+// permanentTwilioCodes are Twilio error codes for requests that will never succeed.
+// See https://www.twilio.com/docs/api/errors.
+var permanentTwilioCodes = map[int]bool{
+	21211: true, // invalid 'To' phone number
+	21212: true, // invalid 'From' phone number
+	21408: true, // permission to send to the region not enabled
+	21610: true, // recipient has unsubscribed
+	21612: true, // cannot route between these numbers
+	21614: true, // 'To' number is not a valid mobile number
+}
+
+// classifyTwilioError marks errors that retrying cannot fix as permanent: known
+// permanent error codes and any 4xx other than rate limiting. Server errors and
+// network failures stay transient.
+func classifyTwilioError(err error) error {
+	var restErr *twilioClient.TwilioRestError
+	if !errors.As(err, &restErr) {
+		return err
+	}
+	if permanentTwilioCodes[restErr.Code] || isPermanentStatus(restErr.Status) {
+		return Permanent(err)
+	}
+	return err
+}
+
+// classifySMTPError marks 5xx SMTP replies, such as an unknown mailbox, as permanent.
+func classifySMTPError(err error) error {
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) && smtpErr.Code >= 500 {
+		return Permanent(err)
+	}
+	return err
+}
+
+// isPermanentStatus reports whether an HTTP status means the request should not
+// be retried: any 4xx except request timeout and rate limiting.
+func isPermanentStatus(status int) bool {
+	return status >= 400 && status < 500 &&
+		status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// statusError builds the error for a non-2xx response, classified by status.
+func statusError(service string, status int, body string) error {
+	err := fmt.Errorf("%s returned status %d: %s", service, status, strings.TrimSpace(body))
+	if isPermanentStatus(status) {
+		return Permanent(err)
+	}
+	return err
+}
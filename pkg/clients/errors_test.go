@@ -0,0 +1,95 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	twilioClient "github.com/twilio/twilio-go/client"
+)
+
+func TestIsPermanent(t *testing.T) {
+	transient := errors.New("connection reset")
+	permanent := Permanent(errors.New("invalid number"))
+
+	assert.False(t, IsPermanent(nil))
+	assert.False(t, IsPermanent(transient))
+	assert.True(t, IsPermanent(permanent))
+	assert.True(t, IsPermanent(fmt.Errorf("twilio error: %w", permanent)))
+	assert.True(t, IsPermanent(errors.Join(permanent, Permanent(errors.New("unsubscribed")))))
+	assert.False(t, IsPermanent(errors.Join(permanent, transient)))
+	assert.True(t, IsPermanent(&RecipientError{Recipient: "+44", Err: permanent}))
+	assert.Nil(t, Permanent(nil))
+}
+
+func TestFailedRecipients(t *testing.T) {
+	err := errors.Join(
+		&RecipientError{Recipient: "111", Err: errors.New("timeout")},
+		&RecipientError{Recipient: "222", Err: errors.New("timeout")},
+	)
+
+	assert.Equal(t, []string{"111", "222"}, FailedRecipients(err))
+	assert.Equal(t, []string{"111"}, FailedRecipients(&RecipientError{Recipient: "111", Err: errors.New("x")}))
+	assert.Nil(t, FailedRecipients(errors.New("whole message failed")))
+	assert.Nil(t, FailedRecipients(errors.Join(err, errors.New("other"))))
+	assert.Nil(t, FailedRecipients(nil))
+}
+
+func TestClassifyTwilioError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{"invalid to number", &twilioClient.TwilioRestError{Code: 21211, Status: 400}, true},
+		{"unsubscribed", &twilioClient.TwilioRestError{Code: 21610, Status: 400}, true},
+		{"unauthorised", &twilioClient.TwilioRestError{Code: 20003, Status: 401}, true},
+		{"rate limited", &twilioClient.TwilioRestError{Code: 20429, Status: 429}, false},
+		{"service unavailable", &twilioClient.TwilioRestError{Code: 20503, Status: 503}, false},
+		{"network error", errors.New("dial tcp: i/o timeout"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.permanent, IsPermanent(classifyTwilioError(tt.err)))
+		})
+	}
+	assert.Nil(t, classifyTwilioError(nil))
+}
+
+func TestClassifySMTPError(t *testing.T) {
+	assert.True(t, IsPermanent(classifySMTPError(&textproto.Error{Code: 550, Msg: "mailbox unavailable"})))
+	assert.False(t, IsPermanent(classifySMTPError(&textproto.Error{Code: 421, Msg: "try again later"})))
+	assert.False(t, IsPermanent(classifySMTPError(errors.New("connection refused"))))
+	assert.Nil(t, classifySMTPError(nil))
+}
+
+func TestStatusError(t *testing.T) {
+	err := statusError("webhook", http.StatusNotFound, " not found\n")
+	assert.EqualError(t, err, "webhook returned status 404: not found")
+	assert.True(t, IsPermanent(err))
+
+	assert.False(t, IsPermanent(statusError("webhook", http.StatusTooManyRequests, "")))
+	assert.False(t, IsPermanent(statusError("webhook", http.StatusRequestTimeout, "")))
+	assert.False(t, IsPermanent(statusError("webhook", http.StatusBadGateway, "")))
+}
+
+func TestHTTPChannels_ClassifyStatus(t *testing.T) {
+	notFound, _ := newRecordingServer(t, http.StatusNotFound)
+	unavailable, _ := newRecordingServer(t, http.StatusServiceUnavailable)
+
+	for _, url := range []string{notFound.URL, unavailable.URL} {
+		channel, err := NewWebhookChannel("webhook", config.WebhookConfig{URL: url})
+		require.NoError(t, err)
+
+		err = channel.Send(testWebhookMessage(), false)
+
+		require.Error(t, err)
+		assert.Equal(t, url == notFound.URL, IsPermanent(err))
+	}
+}
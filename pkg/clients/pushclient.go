@@ -0,0 +1,142 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/templates"
+)
+
+// BinTypeTag returns the emoji shortcode for a bin type, defaulting to a wastebasket.
+func BinTypeTag(binType string) string {
+	return templates.Category(binType).Tag
+}
+
+// PushChannel sends messages to a self-hosted ntfy or Gotify server.
+type PushChannel struct {
+	name   string
+	server string
+	cfg    config.PushConfig
+	client *http.Client
+}
+
+// NewNtfyChannel creates a PushChannel that publishes to an ntfy topic.
+func NewNtfyChannel(name string, cfg config.PushConfig) *PushChannel {
+	return newPushChannel(name, "ntfy", cfg)
+}
+
+// NewGotifyChannel creates a PushChannel that posts to a Gotify server.
+func NewGotifyChannel(name string, cfg config.PushConfig) *PushChannel {
+	return newPushChannel(name, "gotify", cfg)
+}
+
+func newPushChannel(name string, server string, cfg config.PushConfig) *PushChannel {
+	if name == "" {
+		name = server
+	}
+	return &PushChannel{
+		name:   name,
+		server: server,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *PushChannel) Name() string {
+	return c.name
+}
+
+func (c *PushChannel) Send(msg Message, dryRun bool) error {
+	req, err := c.newRequest(msg)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		log.Printf("DRY RUN: Would have sent %s push to %s with title: %s", c.server, req.URL, msg.Title)
+		return nil
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return statusError(c.server, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// tags returns the configured tags followed by one emoji tag per bin type.
+func (c *PushChannel) tags(msg Message) []string {
+	tags := append([]string{}, c.cfg.Tags...)
+	seen := make(map[string]bool)
+	for _, t := range tags {
+		seen[t] = true
+	}
+	for _, binType := range msg.Types {
+		tag := BinTypeTag(binType)
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (c *PushChannel) newRequest(msg Message) (*http.Request, error) {
+	var req *http.Request
+	var err error
+
+	switch c.server {
+	case "ntfy":
+		req, err = http.NewRequest(http.MethodPost, c.cfg.URL+"/"+c.cfg.Topic, strings.NewReader(msg.Body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Title", msg.Title)
+		if c.cfg.Priority > 0 {
+			req.Header.Set("Priority", strconv.Itoa(c.cfg.Priority))
+		}
+		if tags := c.tags(msg); len(tags) > 0 {
+			req.Header.Set("Tags", strings.Join(tags, ","))
+		}
+	case "gotify":
+		payload := struct {
+			Title    string `json:"title"`
+			Message  string `json:"message"`
+			Priority int    `json:"priority,omitempty"`
+		}{
+			Title:    msg.Title,
+			Message:  msg.Body,
+			Priority: c.cfg.Priority,
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		req, err = http.NewRequest(http.MethodPost, c.cfg.URL+"/message", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+	default:
+		return nil, fmt.Errorf("unknown push server: %q", c.server)
+	}
+
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+	return req, nil
+}
@@ -0,0 +1,149 @@
+package clients
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+func newRecordingServer(t *testing.T, status int) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			header: r.Header.Clone(),
+			body:   string(body),
+		})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func testPushMessage() Message {
+	return Message{
+		Title:    "Home: bin collection tomorrow",
+		Body:     "Home: Tomorrows bin collections are: Recycling, Garden Waste",
+		Location: "Home",
+		Types:    []string{"Recycling", "Garden Waste"},
+	}
+}
+
+func TestNtfyChannel_PostsToTopic(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewNtfyChannel("", config.PushConfig{
+		URL:      server.URL,
+		Topic:    "bins",
+		Token:    "tk_secret",
+		Priority: 4,
+		Tags:     []string{"bin"},
+	})
+
+	err := channel.Send(testPushMessage(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ntfy", channel.Name())
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodPost, req.method)
+	assert.Equal(t, "/bins", req.path)
+	assert.Equal(t, "Home: bin collection tomorrow", req.header.Get("Title"))
+	assert.Equal(t, "4", req.header.Get("Priority"))
+	assert.Equal(t, "bin,recycle,deciduous_tree", req.header.Get("Tags"))
+	assert.Equal(t, "Bearer tk_secret", req.header.Get("Authorization"))
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling, Garden Waste", req.body)
+}
+
+func TestNtfyChannel_OmitsOptionalHeaders(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewNtfyChannel("push", config.PushConfig{URL: server.URL, Topic: "bins"})
+
+	err := channel.Send(Message{Title: "Title", Body: "Body"}, false)
+
+	require.NoError(t, err)
+	req := (*requests)[0]
+	assert.Empty(t, req.header.Get("Priority"))
+	assert.Empty(t, req.header.Get("Tags"))
+	assert.Empty(t, req.header.Get("Authorization"))
+}
+
+func TestGotifyChannel_PostsJSONMessage(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewGotifyChannel("", config.PushConfig{
+		URL:      server.URL,
+		Token:    "app-token",
+		Priority: 8,
+	})
+
+	err := channel.Send(testPushMessage(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "gotify", channel.Name())
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "/message", req.path)
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, "Bearer app-token", req.header.Get("Authorization"))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(req.body), &payload))
+	assert.Equal(t, "Home: bin collection tomorrow", payload["title"])
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling, Garden Waste", payload["message"])
+	assert.Equal(t, float64(8), payload["priority"])
+}
+
+func TestPushChannel_DryRunDoesNotPost(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewNtfyChannel("ntfy", config.PushConfig{URL: server.URL, Topic: "bins"})
+
+	err := channel.Send(testPushMessage(), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 0)
+}
+
+func TestPushChannel_Non2xxReturnsError(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusForbidden)
+	channel := NewGotifyChannel("gotify", config.PushConfig{URL: server.URL})
+
+	err := channel.Send(testPushMessage(), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "gotify returned status 403")
+}
+
+func TestBinTypeTag(t *testing.T) {
+	tests := []struct {
+		binType  string
+		expected string
+	}{
+		{"Recycling", "recycle"},
+		{"Garden Waste", "deciduous_tree"},
+		{"Food waste", "green_apple"},
+		{"General Waste", "wastebasket"},
+		{"Household waste", "wastebasket"},
+		{"Something else", "wastebasket"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.binType, func(t *testing.T) {
+			assert.Equal(t, test.expected, BinTypeTag(test.binType))
+		})
+	}
+}
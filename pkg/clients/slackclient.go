@@ -0,0 +1,96 @@
+package clients
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+)
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackPayload struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// slackMaxFields is the maximum number of fields Slack allows in a section block.
+const slackMaxFields = 10
+
+// SlackChannel posts Block Kit cards to a Slack incoming webhook.
+type SlackChannel struct {
+	name   string
+	cfg    config.ChatConfig
+	colors map[string]string
+	client *http.Client
+}
+
+// NewSlackChannel creates a SlackChannel. colors maps location labels to card colours.
+func NewSlackChannel(name string, cfg config.ChatConfig, colors map[string]string) *SlackChannel {
+	if name == "" {
+		name = "slack"
+	}
+	return &SlackChannel{
+		name:   name,
+		cfg:    cfg,
+		colors: colors,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *SlackChannel) Name() string {
+	return c.name
+}
+
+// Route returns the location whose colour msg's card uses, or "" for the defaults.
+func (c *SlackChannel) Route(msg Message) string {
+	return colorRoute(msg, c.colors)
+}
+
+func (c *SlackChannel) Send(msg Message, dryRun bool) error {
+	payload := c.buildPayload(msg)
+	if dryRun {
+		log.Printf("DRY RUN: Would have sent Slack card with title: %s", msg.Title)
+		return nil
+	}
+	return postJSON(c.client, "slack", c.cfg.WebhookURL, payload)
+}
+
+func (c *SlackChannel) buildPayload(msg Message) slackPayload {
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: msg.Title}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: cardSummary(msg)}},
+	}
+
+	var fields []slackText
+	for _, t := range msg.Types {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: ":" + BinTypeTag(t) + ": *" + t + "*"})
+	}
+	for len(fields) > 0 {
+		n := min(len(fields), slackMaxFields)
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields[:n]})
+		fields = fields[n:]
+	}
+
+	return slackPayload{
+		Text: msg.Body,
+		Attachments: []slackAttachment{
+			{Color: cardColor(msg, c.colors), Blocks: blocks},
+		},
+	}
+}
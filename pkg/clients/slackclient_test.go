@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCardMessage(kind MessageKind) Message {
+	return Message{
+		Kind:     kind,
+		Title:    "Home: bin collection tomorrow",
+		Body:     "Home: Tomorrows bin collections are: Recycling, Garden Waste",
+		Location: "Home",
+		PostCode: "RG12 1AB",
+		Date:     time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+		Types:    []string{"Recycling", "Garden Waste"},
+	}
+}
+
+func TestSlackChannel_PostsBlockKitCard(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewSlackChannel("", config.ChatConfig{WebhookURL: server.URL + "/services/T/B/X"}, map[string]string{"Home": "#1E88E5"})
+
+	err := channel.Send(testCardMessage(KindCollection), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "slack", channel.Name())
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/services/T/B/X", (*requests)[0].path)
+	assert.JSONEq(t, `{
+		"text": "Home: Tomorrows bin collections are: Recycling, Garden Waste",
+		"attachments": [{
+			"color": "#1E88E5",
+			"blocks": [
+				{"type": "header", "text": {"type": "plain_text", "text": "Home: bin collection tomorrow"}},
+				{"type": "section", "text": {"type": "mrkdwn", "text": "Bins to put out for collection on Friday 16 January."}},
+				{"type": "section", "fields": [
+					{"type": "mrkdwn", "text": ":recycle: *Recycling*"},
+					{"type": "mrkdwn", "text": ":deciduous_tree: *Garden Waste*"}
+				]}
+			]
+		}]
+	}`, (*requests)[0].body)
+}
+
+func TestSlackChannel_WarningUsesDefaultAmber(t *testing.T) {
+	channel := NewSlackChannel("slack", config.ChatConfig{}, nil)
+
+	payload := channel.buildPayload(testCardMessage(KindScheduleWarning))
+
+	assert.Equal(t, "#FFA000", payload.Attachments[0].Color)
+	assert.Equal(t, "Expected collection on Friday 16 January but none is scheduled.", payload.Attachments[0].Blocks[1].Text.Text)
+}
+
+func TestSlackChannel_SplitsFieldsAcrossSections(t *testing.T) {
+	channel := NewSlackChannel("slack", config.ChatConfig{}, nil)
+	msg := testCardMessage(KindCollection)
+	msg.Types = make([]string, 12)
+	for i := range msg.Types {
+		msg.Types[i] = "Bin"
+	}
+
+	payload := channel.buildPayload(msg)
+
+	blocks := payload.Attachments[0].Blocks
+	require.Len(t, blocks, 4)
+	assert.Len(t, blocks[2].Fields, 10)
+	assert.Len(t, blocks[3].Fields, 2)
+}
+
+func TestSlackChannel_Route(t *testing.T) {
+	channel := NewSlackChannel("slack", config.ChatConfig{}, map[string]string{"Home": "#1E88E5"})
+	msg := testCardMessage(KindCollection)
+
+	assert.Equal(t, "Home", channel.Route(msg))
+	msg.Location = "Office"
+	assert.Empty(t, channel.Route(msg))
+}
+
+func TestSlackChannel_Non2xxDoesNotLeakURL(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusNotFound)
+	channel := NewSlackChannel("slack", config.ChatConfig{WebhookURL: server.URL + "/services/secret"}, nil)
+
+	err := channel.Send(testCardMessage(KindCollection), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "slack returned status 404")
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestSlackChannel_DryRunDoesNotPost(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel := NewSlackChannel("slack", config.ChatConfig{WebhookURL: server.URL}, nil)
+
+	err := channel.Send(testCardMessage(KindCollection), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 0)
+}
+
+func TestSlackChannel_PayloadIsValidJSON(t *testing.T) {
+	channel := NewSlackChannel("slack", config.ChatConfig{}, nil)
+
+	data, err := json.Marshal(channel.buildPayload(Message{Title: "Title", Body: "Body"}))
+
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"fields"`)
+}
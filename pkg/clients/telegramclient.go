@@ -0,0 +1,149 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+)
+
+// telegramEscaper escapes the characters reserved by Telegram's MarkdownV2 parse mode.
+var telegramEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// TelegramChannel sends messages to one or more chats via the Telegram Bot API.
+// Each location can be routed to its own chats; other locations use the default chats.
+type TelegramChannel struct {
+	name   string
+	cfg    config.TelegramConfig
+	routes map[string][]string
+	client *http.Client
+}
+
+// NewTelegramChannel creates a TelegramChannel. routes maps location labels to chat IDs.
+func NewTelegramChannel(name string, cfg config.TelegramConfig, routes map[string][]string) *TelegramChannel {
+	if name == "" {
+		name = "telegram"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.telegram.org"
+	}
+	return &TelegramChannel{
+		name:   name,
+		cfg:    cfg,
+		routes: routes,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *TelegramChannel) Name() string {
+	return c.name
+}
+
+// chatIDs returns the chats a message is sent to: its own recipients if set,
+// otherwise the chats routed for its location or the channel defaults.
+func (c *TelegramChannel) chatIDs(msg Message) []string {
+	if len(msg.To) > 0 {
+		return msg.To
+	}
+	if ids, ok := c.routes[msg.Location]; ok {
+		return ids
+	}
+	return c.cfg.ChatIDs
+}
+
+// Route returns the location whose chats msg is sent to, or "" when it goes to
+// its own recipients or the default chats.
+func (c *TelegramChannel) Route(msg Message) string {
+	if len(msg.To) > 0 {
+		return ""
+	}
+	if _, ok := c.routes[msg.Location]; ok {
+		return msg.Location
+	}
+	return ""
+}
+
+func (c *TelegramChannel) Send(msg Message, dryRun bool) error {
+	chatIDs := c.chatIDs(msg)
+	if len(chatIDs) == 0 {
+		return Permanent(fmt.Errorf("no telegram chat configured for %q", msg.Location))
+	}
+
+	text := formatTelegramMessage(msg)
+	if dryRun {
+		log.Printf("DRY RUN: Would have sent Telegram message to chats %s with text: %s", strings.Join(chatIDs, ", "), text)
+		return nil
+	}
+
+	var errs []error
+	for _, chatID := range chatIDs {
+		if err := c.sendMessage(chatID, text); err != nil {
+			errs = append(errs, &RecipientError{Recipient: chatID, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func formatTelegramMessage(msg Message) string {
+	var b strings.Builder
+	b.WriteString("*" + telegramEscaper.Replace(msg.Title) + "*\n")
+	b.WriteString(telegramEscaper.Replace(msg.Body))
+	if len(msg.Types) > 0 {
+		b.WriteString("\n")
+		for _, t := range msg.Types {
+			b.WriteString("\n• *" + telegramEscaper.Replace(t) + "*")
+		}
+	}
+	return b.String()
+}
+
+func (c *TelegramChannel) sendMessage(chatID string, text string) error {
+	payload := struct {
+		ChatID    string `json:"chat_id"`
+		Text      string `json:"text"`
+		ParseMode string `json:"parse_mode"`
+	}{
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: "MarkdownV2",
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.cfg.BaseURL + "/bot" + c.cfg.Token + "/sendMessage"
+	resp, err := c.client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// The request URL contains the bot token, so don't leak it in the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		result.Description = ""
+	}
+	if !result.OK {
+		return statusError("telegram", resp.StatusCode, result.Description)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type telegramRequest struct {
+	path      string
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+// newFakeBotAPI starts a local stand-in for the Telegram Bot API.
+func newFakeBotAPI(t *testing.T, ok bool) (*httptest.Server, *[]telegramRequest) {
+	t.Helper()
+	var requests []telegramRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req telegramRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		req.path = r.URL.Path
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func testTelegramMessage(location string) Message {
+	return Message{
+		Title:    location + ": bin collection tomorrow",
+		Body:     location + ": Tomorrows bin collections are: Recycling, Garden Waste",
+		Location: location,
+		Types:    []string{"Recycling", "Garden Waste"},
+	}
+}
+
+func TestTelegramChannel_SendsToDefaultChats(t *testing.T) {
+	server, requests := newFakeBotAPI(t, true)
+	channel := NewTelegramChannel("", config.TelegramConfig{
+		Token:   "123:abc",
+		ChatIDs: []string{"111", "222"},
+		BaseURL: server.URL,
+	}, nil)
+
+	err := channel.Send(testTelegramMessage("Home"), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "telegram", channel.Name())
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "/bot123:abc/sendMessage", (*requests)[0].path)
+	assert.Equal(t, "111", (*requests)[0].ChatID)
+	assert.Equal(t, "222", (*requests)[1].ChatID)
+	assert.Equal(t, "MarkdownV2", (*requests)[0].ParseMode)
+}
+
+func TestTelegramChannel_RoutesPerLocation(t *testing.T) {
+	server, requests := newFakeBotAPI(t, true)
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{
+		Token:   "123:abc",
+		ChatIDs: []string{"family"},
+		BaseURL: server.URL,
+	}, map[string][]string{"Office": {"office"}})
+
+	require.NoError(t, channel.Send(testTelegramMessage("Office"), false))
+	require.NoError(t, channel.Send(testTelegramMessage("Home"), false))
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "office", (*requests)[0].ChatID)
+	assert.Equal(t, "family", (*requests)[1].ChatID)
+}
+
+func TestTelegramChannel_MessageRecipientsOverrideRoutes(t *testing.T) {
+	server, requests := newFakeBotAPI(t, true)
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{
+		Token:   "123:abc",
+		ChatIDs: []string{"family"},
+		BaseURL: server.URL,
+	}, map[string][]string{"Office": {"office"}})
+
+	msg := testTelegramMessage("Office")
+	msg.To = []string{"manager"}
+	require.NoError(t, channel.Send(msg, false))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "manager", (*requests)[0].ChatID)
+}
+
+func TestTelegramChannel_Route(t *testing.T) {
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{}, map[string][]string{"Office": {"office"}})
+
+	assert.Equal(t, "Office", channel.Route(testTelegramMessage("Office")))
+	assert.Empty(t, channel.Route(testTelegramMessage("Home")))
+
+	msg := testTelegramMessage("Office")
+	msg.To = []string{"manager"}
+	assert.Empty(t, channel.Route(msg))
+}
+
+func TestTelegramChannel_FormatsBinTypesAsMarkdown(t *testing.T) {
+	text := formatTelegramMessage(testTelegramMessage("Home"))
+
+	assert.Equal(t, "*Home: bin collection tomorrow*\n"+
+		"Home: Tomorrows bin collections are: Recycling, Garden Waste\n"+
+		"\n• *Recycling*"+
+		"\n• *Garden Waste*", text)
+}
+
+func TestTelegramChannel_EscapesReservedCharacters(t *testing.T) {
+	text := formatTelegramMessage(Message{Title: "Flat 1.2 (rear)", Body: "Expected - none!"})
+
+	assert.Equal(t, "*Flat 1\\.2 \\(rear\\)*\nExpected \\- none\\!", text)
+}
+
+func TestTelegramChannel_ReturnsAPIError(t *testing.T) {
+	server, _ := newFakeBotAPI(t, false)
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{
+		Token:   "123:abc",
+		ChatIDs: []string{"111"},
+		BaseURL: server.URL,
+	}, nil)
+
+	err := channel.Send(testTelegramMessage("Home"), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "chat not found")
+	assert.NotContains(t, err.Error(), "123:abc")
+}
+
+func TestTelegramChannel_ReportsEachFailedChat(t *testing.T) {
+	server, requests := newFakeBotAPI(t, false)
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{
+		Token:   "123:abc",
+		ChatIDs: []string{"111", "222"},
+		BaseURL: server.URL,
+	}, nil)
+
+	err := channel.Send(testTelegramMessage("Home"), false)
+
+	assert.Len(t, *requests, 2)
+	assert.Equal(t, []string{"111", "222"}, FailedRecipients(err))
+	assert.True(t, IsPermanent(err))
+}
+
+func TestTelegramChannel_DryRunDoesNotPost(t *testing.T) {
+	server, requests := newFakeBotAPI(t, true)
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{
+		Token:   "123:abc",
+		ChatIDs: []string{"111"},
+		BaseURL: server.URL,
+	}, nil)
+
+	err := channel.Send(testTelegramMessage("Home"), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 0)
+}
+
+func TestTelegramChannel_NoChatsForLocation(t *testing.T) {
+	channel := NewTelegramChannel("telegram", config.TelegramConfig{Token: "123:abc"}, nil)
+
+	err := channel.Send(testTelegramMessage("Home"), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `no telegram chat configured for "Home"`)
+}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"errors"
 	"log"
 
 	twilio "github.com/twilio/twilio-go"
@@ -46,3 +47,42 @@ func (t TwilioClient) SendSms(from string, to string, body string, dryRun bool)
 
 	return t.api.CreateMessage(params)
 }
+
+// TwilioChannel sends messages as SMS via Twilio.
+type TwilioChannel struct {
+	name   string
+	client *TwilioClient
+	from   string
+	to     string
+}
+
+// NewTwilioChannel creates a TwilioChannel sending from and to the given numbers.
+func NewTwilioChannel(name string, client *TwilioClient, from string, to string) *TwilioChannel {
+	if name == "" {
+		name = "twilio"
+	}
+	return &TwilioChannel{
+		name:   name,
+		client: client,
+		from:   from,
+		to:     to,
+	}
+}
+
+func (c *TwilioChannel) Name() string {
+	return c.name
+}
+
+func (c *TwilioChannel) Send(msg Message, dryRun bool) error {
+	if len(msg.To) == 0 {
+		_, err := c.client.SendSms(c.from, c.to, msg.Body, dryRun)
+		return classifyTwilioError(err)
+	}
+	var errs []error
+	for _, to := range msg.To {
+		if _, err := c.client.SendSms(c.from, to, msg.Body, dryRun); err != nil {
+			errs = append(errs, &RecipientError{Recipient: to, Err: classifyTwilioError(err)})
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -86,3 +86,48 @@ func TestSendSms_ReturnsMessageFromTwilio(t *testing.T) {
 	assert.Equal(t, expectedMessage, result)
 	assert.Equal(t, sid, *result.Sid)
 }
+
+func TestTwilioChannel_SendsBodyAsSms(t *testing.T) {
+	mock := &mockMessageCreator{}
+	channel := NewTwilioChannel("", NewTwilioClientWithAPI(mock), "+1234567890", "+0987654321")
+
+	err := channel.Send(Message{Title: "Home", Body: "Home: Tomorrows bin collections are: Recycling"}, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "twilio", channel.Name())
+	assert.True(t, mock.createMessageCalled)
+	assert.Equal(t, "+1234567890", *mock.lastParams.From)
+	assert.Equal(t, "+0987654321", *mock.lastParams.To)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling", *mock.lastParams.Body)
+}
+
+func TestTwilioChannel_DryRunDoesNotCallAPI(t *testing.T) {
+	mock := &mockMessageCreator{}
+	channel := NewTwilioChannel("sms", NewTwilioClientWithAPI(mock), "+1234567890", "+0987654321")
+
+	err := channel.Send(Message{Body: "Test message"}, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "sms", channel.Name())
+	assert.False(t, mock.createMessageCalled)
+}
+
+func TestTwilioChannel_SendsToMessageRecipients(t *testing.T) {
+	var to []string
+	mock := &mockMessageCreator{
+		createMessageFunc: func(params *twilioApi.CreateMessageParams) (*twilioApi.ApiV2010Message, error) {
+			to = append(to, *params.To)
+			if *params.To == "+441111111111" {
+				return nil, errors.New("invalid number")
+			}
+			return &twilioApi.ApiV2010Message{}, nil
+		},
+	}
+	channel := NewTwilioChannel("sms", NewTwilioClientWithAPI(mock), "+1234567890", "+0987654321")
+
+	err := channel.Send(Message{Body: "Test message", To: []string{"+441111111111", "+442222222222"}}, false)
+
+	assert.Equal(t, []string{"+441111111111", "+442222222222"}, to)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "+441111111111: invalid number")
+}
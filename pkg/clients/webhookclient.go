@@ -0,0 +1,143 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of the request body.
+const SignatureHeader = "X-Bin-Notifier-Signature"
+
+// WebhookPayload is the JSON document posted by the webhook channel, and the data
+// available to a user-supplied payload template.
+type WebhookPayload struct {
+	Kind     MessageKind `json:"kind"`
+	Title    string      `json:"title"`
+	Message  string      `json:"message"`
+	Location string      `json:"location"`
+	PostCode string      `json:"postcode"`
+	Date     string      `json:"date"`
+	Types    []string    `json:"types"`
+}
+
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// WebhookChannel posts a JSON document describing each message to a URL.
+type WebhookChannel struct {
+	name     string
+	cfg      config.WebhookConfig
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhookChannel creates a WebhookChannel, parsing the payload template if one is configured.
+func NewWebhookChannel(name string, cfg config.WebhookConfig) (*WebhookChannel, error) {
+	if name == "" {
+		name = "webhook"
+	}
+	c := &WebhookChannel{
+		name:   name,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+	if cfg.Template != "" {
+		tmpl, err := template.New(name).Funcs(webhookFuncs).Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		c.template = tmpl
+	}
+	return c, nil
+}
+
+func (c *WebhookChannel) Name() string {
+	return c.name
+}
+
+func (c *WebhookChannel) Send(msg Message, dryRun bool) error {
+	body, err := c.buildBody(msg)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		log.Printf("DRY RUN: Would have posted webhook to %s with body: %s", c.cfg.URL, body)
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if c.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(c.cfg.Secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return statusError("webhook", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (c *WebhookChannel) buildBody(msg Message) ([]byte, error) {
+	payload := WebhookPayload{
+		Kind:     msg.Kind,
+		Title:    msg.Title,
+		Message:  msg.Body,
+		Location: msg.Location,
+		PostCode: msg.PostCode,
+		Types:    msg.Types,
+	}
+	if !msg.Date.IsZero() {
+		payload.Date = msg.Date.Format("2006-01-02")
+	}
+
+	if c.template == nil {
+		return json.Marshal(payload)
+	}
+
+	var buf bytes.Buffer
+	if err := c.template.Execute(&buf, payload); err != nil {
+		return nil, Permanent(fmt.Errorf("webhook template: %w", err))
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, Permanent(fmt.Errorf("webhook template produced invalid JSON: %s", buf.String()))
+	}
+	return buf.Bytes(), nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
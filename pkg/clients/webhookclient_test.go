@@ -0,0 +1,127 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhookMessage() Message {
+	return Message{
+		Kind:     KindScheduleWarning,
+		Title:    "Home: expected collection not scheduled",
+		Body:     "Home: Expected Garden Waste collection tomorrow (Friday) but none scheduled.",
+		Location: "Home",
+		PostCode: "RG12 1AB",
+		Date:     time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+		Types:    []string{"Garden Waste"},
+	}
+}
+
+func TestWebhookChannel_PostsDefaultPayload(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusNoContent)
+	channel, err := NewWebhookChannel("", config.WebhookConfig{
+		URL:     server.URL + "/hooks/bins",
+		Headers: map[string]string{"X-Api-Key": "key"},
+	})
+	require.NoError(t, err)
+
+	err = channel.Send(testWebhookMessage(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "webhook", channel.Name())
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "/hooks/bins", req.path)
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, "key", req.header.Get("X-Api-Key"))
+	assert.Empty(t, req.header.Get(SignatureHeader))
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal([]byte(req.body), &payload))
+	assert.Equal(t, WebhookPayload{
+		Kind:     KindScheduleWarning,
+		Title:    "Home: expected collection not scheduled",
+		Message:  "Home: Expected Garden Waste collection tomorrow (Friday) but none scheduled.",
+		Location: "Home",
+		PostCode: "RG12 1AB",
+		Date:     "2026-01-16",
+		Types:    []string{"Garden Waste"},
+	}, payload)
+}
+
+func TestWebhookChannel_SignsBody(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel, err := NewWebhookChannel("webhook", config.WebhookConfig{URL: server.URL, Secret: "s3cret"})
+	require.NoError(t, err)
+
+	err = channel.Send(testWebhookMessage(), false)
+
+	require.NoError(t, err)
+	req := (*requests)[0]
+	assert.Equal(t, "sha256="+Sign("s3cret", []byte(req.body)), req.header.Get(SignatureHeader))
+}
+
+func TestWebhookChannel_RendersTemplate(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel, err := NewWebhookChannel("webhook", config.WebhookConfig{
+		URL:      server.URL,
+		Template: `{"text": {{json .Message}}, "bins": {{json (join .Types " & ")}}, "warning": {{if eq .Kind "schedule_warning"}}true{{else}}false{{end}}}`,
+	})
+	require.NoError(t, err)
+
+	err = channel.Send(testWebhookMessage(), false)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"text": "Home: Expected Garden Waste collection tomorrow (Friday) but none scheduled.",
+		"bins": "Garden Waste",
+		"warning": true
+	}`, (*requests)[0].body)
+}
+
+func TestWebhookChannel_InvalidTemplateFailsAtConstruction(t *testing.T) {
+	_, err := NewWebhookChannel("webhook", config.WebhookConfig{URL: "http://example.com", Template: `{{.Message`})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid webhook template")
+}
+
+func TestWebhookChannel_TemplateMustProduceJSON(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel, err := NewWebhookChannel("webhook", config.WebhookConfig{URL: server.URL, Template: `text={{.Message}}`})
+	require.NoError(t, err)
+
+	err = channel.Send(testWebhookMessage(), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid JSON")
+	assert.Len(t, *requests, 0)
+}
+
+func TestWebhookChannel_Non2xxReturnsError(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusBadGateway)
+	channel, err := NewWebhookChannel("webhook", config.WebhookConfig{URL: server.URL})
+	require.NoError(t, err)
+
+	err = channel.Send(testWebhookMessage(), false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook returned status 502")
+}
+
+func TestWebhookChannel_DryRunDoesNotPost(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	channel, err := NewWebhookChannel("webhook", config.WebhookConfig{URL: server.URL})
+	require.NoError(t, err)
+
+	err = channel.Send(testWebhookMessage(), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 0)
+}
@@ -3,39 +3,78 @@ package config
 import (
 	"flag"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	// Embed the time zone database so timezone works in minimal containers.
+	_ "time/tzdata"
 
+	"github.com/robfig/cron/v3"
 	"github.com/stebennett/bin-notifier/pkg/dateutil"
+	"github.com/stebennett/bin-notifier/pkg/holidays"
+	"github.com/stebennett/bin-notifier/pkg/schedule/rrule"
+	"github.com/stebennett/bin-notifier/pkg/scraper"
+	"github.com/stebennett/bin-notifier/pkg/templates"
 	"gopkg.in/yaml.v3"
 )
 
+var colorExp = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
 type Flags struct {
 	ConfigFile string
 	DryRun     bool
 	TodayDate  string
+	StateFile  string
+	Force      bool
+	Serve      bool
+	// ListScrapers prints the available council scrapers instead of running.
+	ListScrapers bool
 }
 
+// ParseFlags parses the command line. A leading "serve" argument selects daemon
+// mode, which runs the notifier on the configured schedule instead of once, and
+// a leading "scrapers" argument lists the available council scrapers, including
+// the recipes of the config file when one is given.
 func ParseFlags(args []string) (Flags, error) {
 	fs := flag.NewFlagSet("bin-notifier", flag.ContinueOnError)
 
+	var f Flags
+	if len(args) > 0 && args[0] == "scrapers" {
+		f.ListScrapers = true
+		args = args[1:]
+	}
+	if len(args) > 0 && args[0] == "serve" {
+		f.Serve = true
+		args = args[1:]
+	}
+
 	configDefault := os.Getenv("BN_CONFIG_FILE")
 	dryRunDefault := os.Getenv("BN_DRY_RUN") == "true"
 	todayDateDefault := os.Getenv("BN_TODAY_DATE")
+	stateFileDefault := os.Getenv("BN_STATE_FILE")
+	forceDefault := os.Getenv("BN_FORCE") == "true"
 
-	var f Flags
 	fs.StringVar(&f.ConfigFile, "c", configDefault, "path to YAML config file")
 	fs.StringVar(&f.ConfigFile, "config", configDefault, "path to YAML config file")
 	fs.BoolVar(&f.DryRun, "x", dryRunDefault, "dry-run mode (no SMS sent)")
 	fs.BoolVar(&f.DryRun, "dryrun", dryRunDefault, "dry-run mode (no SMS sent)")
 	fs.StringVar(&f.TodayDate, "d", todayDateDefault, "override today's date (YYYY-MM-DD)")
 	fs.StringVar(&f.TodayDate, "todaydate", todayDateDefault, "override today's date (YYYY-MM-DD)")
+	fs.StringVar(&f.StateFile, "s", stateFileDefault, "path to notification history file (enables de-duplication)")
+	fs.StringVar(&f.StateFile, "statefile", stateFileDefault, "path to notification history file (enables de-duplication)")
+	fs.BoolVar(&f.Force, "f", forceDefault, "send notifications even if already delivered")
+	fs.BoolVar(&f.Force, "force", forceDefault, "send notifications even if already delivered")
 
 	if err := fs.Parse(args); err != nil {
 		return Flags{}, err
 	}
 
-	if f.ConfigFile == "" {
+	if f.ConfigFile == "" && !f.ListScrapers {
 		return Flags{}, fmt.Errorf("config file is required (-c or BN_CONFIG_FILE)")
 	}
 
@@ -48,22 +87,237 @@ type CollectionDay struct {
 	Types         []string     `yaml:"types"`
 	EveryNWeeks   int          `yaml:"every_n_weeks"`
 	ReferenceDate string       `yaml:"reference_date"`
+	// RRule is an RFC 5545 recurrence rule used instead of day and
+	// every_n_weeks, with ReferenceDate as its start date.
+	RRule string      `yaml:"rrule"`
+	Rule  *rrule.Rule `yaml:"-"`
+	// ActiveFrom and ActiveUntil limit the collections to a window, inclusive.
+	// Each is a month-day (MM-DD), repeating every year, or a date (YYYY-MM-DD).
+	// A month-day window whose start is after its end spans the new year.
+	ActiveFrom  string `yaml:"active_from"`
+	ActiveUntil string `yaml:"active_until"`
 }
 
 type Location struct {
-	Label          string          `yaml:"label"`
-	Scraper        string          `yaml:"scraper"`
-	PostCode       string          `yaml:"postcode"`
-	AddressCode    string          `yaml:"address_code"`
-	CollectionDays []CollectionDay `yaml:"collection_days"`
+	Label           string           `yaml:"label"`
+	Scraper         string           `yaml:"scraper"`
+	PostCode        string           `yaml:"postcode"`
+	AddressCode     string           `yaml:"address_code"`
+	CollectionDays  []CollectionDay  `yaml:"collection_days"`
+	TelegramChatIDs []string         `yaml:"telegram_chat_ids"`
+	Color           string           `yaml:"color"`
+	Templates       MessageTemplates `yaml:"templates"`
+	Recipients      []Recipient      `yaml:"recipients"`
+	Reminders       []Reminder       `yaml:"reminders"`
+	// NotifyDaysBefore adds a put_out reminder this many days before each collection.
+	NotifyDaysBefore []int        `yaml:"notify_days_before"`
+	HolidayShift     HolidayShift `yaml:"holiday_shift"`
+	Exceptions       []Exception  `yaml:"exceptions"`
+	// ScrapeTimeout bounds each scrape of the council website.
+	ScrapeTimeout time.Duration `yaml:"scrape_timeout"`
+}
+
+// Exception changes a location's expected collections on particular dates
+// (YYYY-MM-DD). Set one of Skip (no collection that day), From and To (the
+// collection moves) or Extra (a one-off collection of Types). For skips and
+// moves, Types limits the exception to those bin types; otherwise every
+// collection that day is affected.
+type Exception struct {
+	Skip  string   `yaml:"skip"`
+	From  string   `yaml:"from"`
+	To    string   `yaml:"to"`
+	Extra string   `yaml:"extra"`
+	Types []string `yaml:"types"`
 }
 
+// Holiday shift scopes: every collection later in the week of a bank holiday
+// moves, or only a collection falling on the holiday itself.
+const (
+	HolidayScopeWeek = "week"
+	HolidayScopeDay  = "day"
+)
+
+// HolidayShift moves a location's expected collections by Days after a bank
+// holiday. Scope "week" (default) moves every collection on or after a holiday
+// in the same Monday to Sunday week; "day" only moves a collection that falls
+// on the holiday. Days 0 disables shifting.
+type HolidayShift struct {
+	Days  int    `yaml:"days"`
+	Scope string `yaml:"scope"`
+}
+
+// HolidaysConfig selects the bank holiday calendar: "england-and-wales"
+// (default) or "none", plus any extra dates (YYYY-MM-DD).
+type HolidaysConfig struct {
+	Calendar string   `yaml:"calendar"`
+	Dates    []string `yaml:"dates"`
+}
+
+// Reminder actions: put the bins out before a collection, or bring them back in
+// afterwards.
+const (
+	ActionPutOut  = "put_out"
+	ActionBringIn = "bring_in"
+)
+
+// Reminder schedules a notification relative to a collection day. Offset is in
+// days, so -1 is the day before and 0 the collection day itself, and the reminder
+// falls due once the local time reaches Time (HH:MM, default 00:00). Action
+// defaults to put_out for reminders before the collection day and bring_in on it.
+type Reminder struct {
+	Offset    int           `yaml:"offset"`
+	RawTime   string        `yaml:"time"`
+	Action    string        `yaml:"action"`
+	TimeOfDay time.Duration `yaml:"-"`
+}
+
+// Recipient routes messages on a named channel to one address: a phone number for
+// twilio, an email address for email or a chat ID for telegram. When Types is set
+// the recipient only receives messages that include one of those bin types.
+type Recipient struct {
+	Channel string   `yaml:"channel"`
+	Address string   `yaml:"address"`
+	Types   []string `yaml:"types"`
+}
+
+// HasRecipient reports whether any recipient is on the named channel.
+func HasRecipient(recipients []Recipient, channel string) bool {
+	for _, r := range recipients {
+		if r.Channel == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageTemplates holds text/template overrides for each kind of message. Empty
+// fields fall back to the next level: channel, then location, then global, then
+// the built-in wording.
+type MessageTemplates struct {
+	Collection      string `yaml:"collection"`
+	ScheduleWarning string `yaml:"schedule_warning"`
+	BringIn         string `yaml:"bring_in"`
+}
+
+// ChannelConfig selects a notification channel by type. Name defaults to the type
+// and must be unique across channels.
+type ChannelConfig struct {
+	Type      string           `yaml:"type"`
+	Name      string           `yaml:"name"`
+	Templates MessageTemplates `yaml:"templates"`
+	Email     EmailConfig      `yaml:"email"`
+	Push      PushConfig       `yaml:"push"`
+	Telegram  TelegramConfig   `yaml:"telegram"`
+	Webhook   WebhookConfig    `yaml:"webhook"`
+	Slack     ChatConfig       `yaml:"slack"`
+	Discord   ChatConfig       `yaml:"discord"`
+}
+
+// EmailConfig holds SMTP settings for the email channel. TLS is one of
+// "starttls" (default), "implicit" or "none".
+type EmailConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	TLS      string   `yaml:"tls"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// PushConfig holds settings for the ntfy and gotify push channels. Topic is only
+// used by ntfy; Priority 0 selects the server default.
+type PushConfig struct {
+	URL      string   `yaml:"url"`
+	Topic    string   `yaml:"topic"`
+	Token    string   `yaml:"token"`
+	Priority int      `yaml:"priority"`
+	Tags     []string `yaml:"tags"`
+}
+
+// TelegramConfig holds Telegram Bot API settings. ChatIDs are the default
+// recipients for locations without their own telegram_chat_ids.
+type TelegramConfig struct {
+	Token   string   `yaml:"token"`
+	ChatIDs []string `yaml:"chat_ids"`
+	BaseURL string   `yaml:"base_url"`
+}
+
+// WebhookConfig holds settings for the generic webhook channel. When Secret is set
+// the body is signed with HMAC-SHA256; Template overrides the default JSON payload.
+type WebhookConfig struct {
+	URL      string            `yaml:"url"`
+	Headers  map[string]string `yaml:"headers"`
+	Secret   string            `yaml:"secret"`
+	Template string            `yaml:"template"`
+}
+
+// ChatConfig holds the incoming-webhook URL for the Slack and Discord channels.
+type ChatConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// RetryConfig controls how failed channel sends are retried. Delays double after
+// each failure, starting at InitialDelay and capped at MaxDelay, with jitter.
+type RetryConfig struct {
+	MaxAttempts  int           `yaml:"max_attempts"`
+	InitialDelay time.Duration `yaml:"initial_delay"`
+	MaxDelay     time.Duration `yaml:"max_delay"`
+}
+
+// MQTTConfig enables publishing collection state to Home Assistant via MQTT
+// discovery. Publishing is disabled when Broker is empty.
+type MQTTConfig struct {
+	Broker          string `yaml:"broker"`
+	Username        string `yaml:"username"`
+	Password        string `yaml:"password"`
+	ClientID        string `yaml:"client_id"`
+	DiscoveryPrefix string `yaml:"discovery_prefix"`
+	BaseTopic       string `yaml:"base_topic"`
+}
+
+// DaemonConfig controls serve mode. Schedule is a standard five-field cron
+// expression evaluated in the configured time zone, and CacheTTL is how long
+// scraped collections are reused between runs.
+type DaemonConfig struct {
+	Schedule string        `yaml:"schedule"`
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// BrowserConfig controls the headless Chrome shared by scrapers. MaxTabs is
+// how many scrapes can use it at once.
+type BrowserConfig struct {
+	MaxTabs int `yaml:"max_tabs"`
+}
+
+// Config is the notifier's YAML config. Timezone is an IANA zone name (default
+// Europe/London) used to work out today's date and to read collection dates;
+// Zone holds the loaded zone after validation.
 type Config struct {
-	FromNumber string     `yaml:"from_number"`
-	ToNumber   string     `yaml:"to_number"`
-	Locations  []Location `yaml:"locations"`
-	DryRun     bool       `yaml:"-"`
-	TodayDate  string     `yaml:"-"`
+	Timezone   string           `yaml:"timezone"`
+	Zone       *time.Location   `yaml:"-"`
+	FromNumber string           `yaml:"from_number"`
+	ToNumber   string           `yaml:"to_number"`
+	Channels   []ChannelConfig  `yaml:"channels"`
+	Recipients []Recipient      `yaml:"recipients"`
+	Templates  MessageTemplates `yaml:"templates"`
+	Digest     bool             `yaml:"digest"`
+	Retry      RetryConfig      `yaml:"retry"`
+	MQTT       MQTTConfig       `yaml:"mqtt"`
+	Daemon     DaemonConfig     `yaml:"daemon"`
+	Browser    BrowserConfig    `yaml:"browser"`
+	Holidays   HolidaysConfig   `yaml:"holidays"`
+	// ScrapersDir holds YAML scraper recipes, relative to the config file.
+	ScrapersDir string `yaml:"scrapers_dir"`
+	// Recipes are loaded from ScrapersDir. Loading a config doesn't register
+	// them; pass them to scraper.RegisterRecipes before creating scrapers.
+	Recipes []*scraper.Recipe `yaml:"-"`
+	// HolidayCalendar is built from Holidays during validation.
+	HolidayCalendar *holidays.Calendar `yaml:"-"`
+	Locations       []Location         `yaml:"locations"`
+	DryRun          bool               `yaml:"-"`
+	TodayDate       string             `yaml:"-"`
+	Force           bool               `yaml:"-"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -83,7 +337,63 @@ func LoadConfig(path string) (Config, error) {
 	if cfg.ToNumber == "" {
 		cfg.ToNumber = os.Getenv("BN_TO_NUMBER")
 	}
+	for i := range cfg.Channels {
+		telegram := &cfg.Channels[i].Telegram
+		if telegram.Token == "" {
+			telegram.Token = os.Getenv("BN_TELEGRAM_TOKEN")
+		}
+		webhook := &cfg.Channels[i].Webhook
+		if webhook.Secret == "" {
+			webhook.Secret = os.Getenv("BN_WEBHOOK_SECRET")
+		}
+		if cfg.Channels[i].Slack.WebhookURL == "" {
+			cfg.Channels[i].Slack.WebhookURL = os.Getenv("BN_SLACK_WEBHOOK_URL")
+		}
+		if cfg.Channels[i].Discord.WebhookURL == "" {
+			cfg.Channels[i].Discord.WebhookURL = os.Getenv("BN_DISCORD_WEBHOOK_URL")
+		}
+	}
+
+	// SMTP credentials belong to one server, so they are only used when there
+	// is a single email channel.
+	if email := channelsOfType(cfg, "email"); len(email) == 1 {
+		smtp := &cfg.Channels[email[0]].Email
+		if smtp.Username == "" {
+			smtp.Username = os.Getenv("BN_SMTP_USERNAME")
+		}
+		if smtp.Password == "" {
+			smtp.Password = os.Getenv("BN_SMTP_PASSWORD")
+		}
+	}
 
+	// A token could belong to either push server, so it is only used when a
+	// single ntfy or Gotify channel has none of its own.
+	var tokenless []int
+	for _, i := range channelsOfType(cfg, "ntfy", "gotify") {
+		if cfg.Channels[i].Push.Token == "" {
+			tokenless = append(tokenless, i)
+		}
+	}
+	if len(tokenless) == 1 {
+		cfg.Channels[tokenless[0]].Push.Token = os.Getenv("BN_PUSH_TOKEN")
+	}
+
+	if cfg.Timezone == "" {
+		cfg.Timezone = os.Getenv("BN_TIMEZONE")
+	}
+	if cfg.Daemon.Schedule == "" {
+		cfg.Daemon.Schedule = os.Getenv("BN_SCHEDULE")
+	}
+	if cfg.MQTT.Username == "" {
+		cfg.MQTT.Username = os.Getenv("BN_MQTT_USERNAME")
+	}
+	if cfg.MQTT.Password == "" {
+		cfg.MQTT.Password = os.Getenv("BN_MQTT_PASSWORD")
+	}
+
+	if err := loadRecipes(&cfg, path); err != nil {
+		return Config{}, err
+	}
 	if err := validate(&cfg); err != nil {
 		return Config{}, err
 	}
@@ -91,6 +401,18 @@ func LoadConfig(path string) (Config, error) {
 	return cfg, nil
 }
 
+// channelsOfType returns the indexes of the channels with one of the given
+// types, before validation has normalised their case.
+func channelsOfType(cfg Config, types ...string) []int {
+	var indexes []int
+	for i, ch := range cfg.Channels {
+		if slices.Contains(types, strings.ToLower(ch.Type)) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
 // LoadConfigForMCP loads config for the MCP server, skipping phone number validation.
 func LoadConfigForMCP(path string) (Config, error) {
 	data, err := os.ReadFile(path)
@@ -103,6 +425,21 @@ func LoadConfigForMCP(path string) (Config, error) {
 		return Config{}, err
 	}
 
+	if cfg.Timezone == "" {
+		cfg.Timezone = os.Getenv("BN_TIMEZONE")
+	}
+	if err := validateTimezone(&cfg); err != nil {
+		return Config{}, err
+	}
+	if err := validateHolidays(&cfg); err != nil {
+		return Config{}, err
+	}
+	if err := validateBrowser(&cfg.Browser); err != nil {
+		return Config{}, err
+	}
+	if err := loadRecipes(&cfg, path); err != nil {
+		return Config{}, err
+	}
 	if err := validateLocations(&cfg); err != nil {
 		return Config{}, err
 	}
@@ -111,13 +448,426 @@ func LoadConfigForMCP(path string) (Config, error) {
 }
 
 func validate(cfg *Config) error {
-	if cfg.FromNumber == "" {
-		return fmt.Errorf("from_number is required")
+	if err := validateTimezone(cfg); err != nil {
+		return err
 	}
-	if cfg.ToNumber == "" {
-		return fmt.Errorf("to_number is required")
+	if err := validateChannels(cfg); err != nil {
+		return err
+	}
+	if err := validateRetry(&cfg.Retry); err != nil {
+		return err
+	}
+	validateMQTT(&cfg.MQTT)
+	if err := validateDaemon(&cfg.Daemon); err != nil {
+		return err
+	}
+	if err := validateBrowser(&cfg.Browser); err != nil {
+		return err
+	}
+	if err := validateHolidays(cfg); err != nil {
+		return err
+	}
+	if err := validateLocations(cfg); err != nil {
+		return err
+	}
+	if err := validateRecipients(cfg); err != nil {
+		return err
+	}
+	return validateTemplates(cfg)
+}
+
+// coveredByRecipients reports whether every location has a recipient on the named
+// channel, either globally or on the location, so the channel's own default
+// addresses are never used.
+func coveredByRecipients(cfg *Config, channel string) bool {
+	if HasRecipient(cfg.Recipients, channel) {
+		return true
+	}
+	if len(cfg.Locations) == 0 {
+		return false
+	}
+	for _, loc := range cfg.Locations {
+		if !HasRecipient(loc.Recipients, channel) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRecipients checks that each recipient names a configured channel that
+// can deliver to individual addresses.
+func validateRecipients(cfg *Config) error {
+	channelTypes := make(map[string]string)
+	for _, ch := range cfg.Channels {
+		channelTypes[ch.Name] = ch.Type
+	}
+	for i, r := range cfg.Recipients {
+		if err := validateRecipient(r, channelTypes); err != nil {
+			return fmt.Errorf("recipient %d: %w", i+1, err)
+		}
+	}
+	for i, loc := range cfg.Locations {
+		for j, r := range loc.Recipients {
+			if err := validateRecipient(r, channelTypes); err != nil {
+				return fmt.Errorf("location %d, recipient %d: %w", i+1, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRecipient(r Recipient, channelTypes map[string]string) error {
+	if r.Channel == "" {
+		return fmt.Errorf("channel is required")
+	}
+	chType, ok := channelTypes[r.Channel]
+	if !ok {
+		return fmt.Errorf("unknown channel %q", r.Channel)
+	}
+	switch chType {
+	case "twilio", "email", "telegram":
+	default:
+		return fmt.Errorf("channel %q does not support recipients", r.Channel)
+	}
+	if r.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	return nil
+}
+
+// validateTemplates renders every configured template against sample data so a
+// typo fails at load time rather than when a message is sent.
+func validateTemplates(cfg *Config) error {
+	if err := validateMessageTemplates(cfg.Templates); err != nil {
+		return fmt.Errorf("templates: %w", err)
+	}
+	for i, ch := range cfg.Channels {
+		if err := validateMessageTemplates(ch.Templates); err != nil {
+			return fmt.Errorf("channel %d: %w", i+1, err)
+		}
+	}
+	for i, loc := range cfg.Locations {
+		if err := validateMessageTemplates(loc.Templates); err != nil {
+			return fmt.Errorf("location %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func validateMessageTemplates(t MessageTemplates) error {
+	if t.Collection != "" {
+		if err := templates.Validate(t.Collection); err != nil {
+			return fmt.Errorf("collection: %w", err)
+		}
+	}
+	if t.ScheduleWarning != "" {
+		if err := templates.Validate(t.ScheduleWarning); err != nil {
+			return fmt.Errorf("schedule_warning: %w", err)
+		}
+	}
+	if t.BringIn != "" {
+		if err := templates.Validate(t.BringIn); err != nil {
+			return fmt.Errorf("bring_in: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateTimezone loads the configured time zone, defaulting to Europe/London.
+func validateTimezone(cfg *Config) error {
+	if cfg.Timezone == "" {
+		cfg.Timezone = "Europe/London"
+	}
+	zone, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone: unknown time zone %q", cfg.Timezone)
+	}
+	cfg.Zone = zone
+	return nil
+}
+
+// validateRetry fills in the default retry policy: three attempts, waiting about
+// 2s then 4s, never more than 30s.
+func validateRetry(r *RetryConfig) error {
+	if r.MaxAttempts < 0 || r.InitialDelay < 0 || r.MaxDelay < 0 {
+		return fmt.Errorf("retry: values must not be negative")
+	}
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = 3
+	}
+	if r.InitialDelay == 0 {
+		r.InitialDelay = 2 * time.Second
+	}
+	if r.MaxDelay == 0 {
+		r.MaxDelay = 30 * time.Second
+	}
+	if r.MaxDelay < r.InitialDelay {
+		return fmt.Errorf("retry: max_delay must be at least initial_delay")
+	}
+	return nil
+}
+
+// validateHolidays builds the holiday calendar from the bundled calendar and any
+// extra dates.
+func validateHolidays(cfg *Config) error {
+	if cfg.Holidays.Calendar == "" {
+		cfg.Holidays.Calendar = "england-and-wales"
+	}
+	cal, err := holidays.Named(cfg.Holidays.Calendar)
+	if err != nil {
+		return fmt.Errorf("holidays: %w", err)
+	}
+	for i, raw := range cfg.Holidays.Dates {
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return fmt.Errorf("holidays: date %d: invalid date %q", i+1, raw)
+		}
+		cal.Add(date)
+	}
+	cfg.HolidayCalendar = cal
+	return nil
+}
+
+// validateDaemon fills in the serve mode defaults: 6pm every day, reusing scraped
+// collections for six hours.
+func validateDaemon(d *DaemonConfig) error {
+	if d.Schedule == "" {
+		d.Schedule = "0 18 * * *"
+	}
+	if _, err := cron.ParseStandard(d.Schedule); err != nil {
+		return fmt.Errorf("daemon: invalid schedule %q: %w", d.Schedule, err)
+	}
+	if d.CacheTTL < 0 {
+		return fmt.Errorf("daemon: cache_ttl must not be negative")
+	}
+	if d.CacheTTL == 0 {
+		d.CacheTTL = 6 * time.Hour
+	}
+	return nil
+}
+
+// validateBrowser defaults the shared browser to two tabs at once.
+func validateBrowser(b *BrowserConfig) error {
+	if b.MaxTabs < 0 {
+		return fmt.Errorf("browser: max_tabs must not be negative")
+	}
+	if b.MaxTabs == 0 {
+		b.MaxTabs = 2
+	}
+	return nil
+}
+
+// LoadRecipes loads the scraper recipes of the config file at path without
+// validating the rest of it. With an empty path only BN_SCRAPERS_DIR is used.
+func LoadRecipes(path string) ([]*scraper.Recipe, error) {
+	var cfg Config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadRecipes(&cfg, path); err != nil {
+		return nil, err
+	}
+	return cfg.Recipes, nil
+}
+
+// loadRecipes loads the scraper recipes in scrapers_dir into cfg.Recipes. A
+// relative scrapers_dir is resolved against the directory of the config file
+// at path.
+func loadRecipes(cfg *Config, path string) error {
+	if cfg.ScrapersDir == "" {
+		cfg.ScrapersDir = os.Getenv("BN_SCRAPERS_DIR")
+	}
+	if cfg.ScrapersDir == "" {
+		return nil
+	}
+	if !filepath.IsAbs(cfg.ScrapersDir) {
+		cfg.ScrapersDir = filepath.Join(filepath.Dir(path), cfg.ScrapersDir)
+	}
+	recipes, err := scraper.LoadRecipes(cfg.ScrapersDir)
+	if err != nil {
+		return fmt.Errorf("scrapers_dir: %w", err)
+	}
+	for _, r := range recipes {
+		if info, ok := scraper.Lookup(r.Name); ok && !info.Recipe {
+			return fmt.Errorf("scrapers_dir: recipe %q: a built-in scraper has that name", r.Name)
+		}
+	}
+	cfg.Recipes = recipes
+	return nil
+}
+
+// lookupScraper finds the named scraper among the config's recipes and the
+// registered scrapers.
+func lookupScraper(cfg *Config, name string) (scraper.Info, bool) {
+	for _, r := range cfg.Recipes {
+		if strings.EqualFold(r.Name, name) {
+			return r.Info(), true
+		}
 	}
-	return validateLocations(cfg)
+	return scraper.Lookup(name)
+}
+
+// scraperNames returns the names of the registered scrapers and the config's
+// recipes, sorted.
+func scraperNames(cfg *Config) []string {
+	names := scraper.Names()
+	for _, r := range cfg.Recipes {
+		if !slices.Contains(names, r.Name) {
+			names = append(names, r.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateMQTT fills in defaults for an enabled MQTT section.
+func validateMQTT(m *MQTTConfig) {
+	if m.Broker == "" {
+		return
+	}
+	if m.ClientID == "" {
+		m.ClientID = "bin-notifier"
+	}
+	if m.DiscoveryPrefix == "" {
+		m.DiscoveryPrefix = "homeassistant"
+	}
+	if m.BaseTopic == "" {
+		m.BaseTopic = "bin-notifier"
+	}
+}
+
+// validateChannels defaults to a single Twilio channel when none are configured,
+// then checks the settings required by each channel type.
+func validateChannels(cfg *Config) error {
+	if len(cfg.Channels) == 0 {
+		cfg.Channels = []ChannelConfig{{Type: "twilio"}}
+	}
+	names := make(map[string]bool)
+	for i := range cfg.Channels {
+		ch := &cfg.Channels[i]
+		if ch.Type == "" {
+			return fmt.Errorf("channel %d: type is required", i+1)
+		}
+		ch.Type = strings.ToLower(ch.Type)
+		if ch.Name == "" {
+			ch.Name = ch.Type
+		}
+		if names[ch.Name] {
+			return fmt.Errorf("channel %d: duplicate name %q", i+1, ch.Name)
+		}
+		names[ch.Name] = true
+
+		switch ch.Type {
+		case "twilio":
+			if cfg.FromNumber == "" {
+				return fmt.Errorf("from_number is required")
+			}
+			if cfg.ToNumber == "" && !coveredByRecipients(cfg, ch.Name) {
+				return fmt.Errorf("to_number is required")
+			}
+		case "email":
+			if err := validateEmail(&ch.Email, coveredByRecipients(cfg, ch.Name)); err != nil {
+				return fmt.Errorf("channel %d: %w", i+1, err)
+			}
+		case "ntfy", "gotify":
+			if err := validatePush(ch.Type, &ch.Push); err != nil {
+				return fmt.Errorf("channel %d: %w", i+1, err)
+			}
+		case "telegram":
+			if err := validateTelegram(&ch.Telegram, ch.Name, cfg); err != nil {
+				return fmt.Errorf("channel %d: %w", i+1, err)
+			}
+		case "webhook":
+			if ch.Webhook.URL == "" {
+				return fmt.Errorf("channel %d: webhook url is required", i+1)
+			}
+		case "slack":
+			if ch.Slack.WebhookURL == "" {
+				return fmt.Errorf("channel %d: slack webhook_url is required", i+1)
+			}
+		case "discord":
+			if ch.Discord.WebhookURL == "" {
+				return fmt.Errorf("channel %d: discord webhook_url is required", i+1)
+			}
+		default:
+			return fmt.Errorf("channel %d: unknown type %q", i+1, ch.Type)
+		}
+	}
+	return nil
+}
+
+func validatePush(chType string, push *PushConfig) error {
+	if push.URL == "" {
+		return fmt.Errorf("push url is required")
+	}
+	push.URL = strings.TrimRight(push.URL, "/")
+	maxPriority := 10
+	if chType == "ntfy" {
+		if push.Topic == "" {
+			return fmt.Errorf("push topic is required for ntfy")
+		}
+		maxPriority = 5
+	}
+	if push.Priority < 0 || push.Priority > maxPriority {
+		return fmt.Errorf("push priority must be between 0 and %d", maxPriority)
+	}
+	return nil
+}
+
+// validateTelegram checks that every location resolves to at least one chat,
+// either through its own telegram_chat_ids, recipients or the channel defaults.
+func validateTelegram(telegram *TelegramConfig, name string, cfg *Config) error {
+	if telegram.Token == "" {
+		return fmt.Errorf("telegram token is required")
+	}
+	if telegram.BaseURL == "" {
+		telegram.BaseURL = "https://api.telegram.org"
+	}
+	telegram.BaseURL = strings.TrimRight(telegram.BaseURL, "/")
+	if len(telegram.ChatIDs) > 0 || HasRecipient(cfg.Recipients, name) {
+		return nil
+	}
+	for _, loc := range cfg.Locations {
+		if len(loc.TelegramChatIDs) == 0 && !HasRecipient(loc.Recipients, name) {
+			return fmt.Errorf("telegram chat_ids is required when location %q has no telegram_chat_ids", loc.Label)
+		}
+	}
+	return nil
+}
+
+func validateEmail(email *EmailConfig, hasRecipients bool) error {
+	if email.Host == "" {
+		return fmt.Errorf("email host is required")
+	}
+	email.TLS = strings.ToLower(email.TLS)
+	defaultPort := 0
+	switch email.TLS {
+	case "", "starttls":
+		email.TLS = "starttls"
+		defaultPort = 587
+	case "implicit":
+		defaultPort = 465
+	case "none":
+		defaultPort = 25
+	default:
+		return fmt.Errorf("invalid email tls mode: %q", email.TLS)
+	}
+	if email.Port == 0 {
+		email.Port = defaultPort
+	}
+	if email.From == "" {
+		return fmt.Errorf("email from is required")
+	}
+	if len(email.To) == 0 && !hasRecipients {
+		return fmt.Errorf("email to must have at least one entry")
+	}
+	return nil
 }
 
 func validateLocations(cfg *Config) error {
@@ -132,17 +882,37 @@ func validateLocations(cfg *Config) error {
 		if loc.Scraper == "" {
 			return fmt.Errorf("location %d: scraper is required", i+1)
 		}
-		if loc.PostCode == "" {
-			return fmt.Errorf("location %d: postcode is required", i+1)
+		info, ok := lookupScraper(cfg, loc.Scraper)
+		if !ok {
+			return fmt.Errorf("location %d: unknown scraper %q (available: %s)", i+1, loc.Scraper, strings.Join(scraperNames(cfg), ", "))
+		}
+		for _, f := range info.Fields {
+			if f.Required && locationField(*loc, f.Name) == "" {
+				return fmt.Errorf("location %d: %s is required", i+1, f.Name)
+			}
 		}
-		if loc.AddressCode == "" {
-			return fmt.Errorf("location %d: address_code is required", i+1)
+		// The district lists may be incomplete, so a postcode outside them is
+		// only a likely mistake.
+		if loc.PostCode != "" && !info.SupportsPostcode(loc.PostCode) {
+			log.Printf("WARNING: location %d: postcode %q is outside scraper %q's postcode districts (%s)", i+1, loc.PostCode, info.Name, strings.Join(info.PostcodePrefixes, ", "))
 		}
 		if len(loc.CollectionDays) == 0 {
 			return fmt.Errorf("location %d: collection_days must have at least one entry", i+1)
 		}
+		if loc.Color != "" && !colorExp.MatchString(loc.Color) {
+			return fmt.Errorf("location %d: color must be a hex colour like #2E7D32", i+1)
+		}
 		for j := range loc.CollectionDays {
 			cd := &loc.CollectionDays[j]
+			if err := validateActiveWindow(*cd); err != nil {
+				return fmt.Errorf("location %d, schedule %d: %w", i+1, j+1, err)
+			}
+			if cd.RRule != "" {
+				if err := validateRRule(cd); err != nil {
+					return fmt.Errorf("location %d, schedule %d: %w", i+1, j+1, err)
+				}
+				continue
+			}
 			if cd.RawDay == "" {
 				return fmt.Errorf("location %d, schedule %d: day is required", i+1, j+1)
 			}
@@ -173,6 +943,158 @@ func validateLocations(cfg *Config) error {
 				}
 			}
 		}
+		if loc.ScrapeTimeout < 0 {
+			return fmt.Errorf("location %d: scrape_timeout must not be negative", i+1)
+		}
+		if loc.ScrapeTimeout == 0 {
+			loc.ScrapeTimeout = scraper.DefaultTimeout
+		}
+		if loc.HolidayShift.Days < 0 || loc.HolidayShift.Days > 6 {
+			return fmt.Errorf("location %d: holiday_shift days must be between 0 and 6", i+1)
+		}
+		switch loc.HolidayShift.Scope {
+		case "":
+			loc.HolidayShift.Scope = HolidayScopeWeek
+		case HolidayScopeWeek, HolidayScopeDay:
+		default:
+			return fmt.Errorf("location %d: unknown holiday_shift scope %q", i+1, loc.HolidayShift.Scope)
+		}
+		for j, e := range loc.Exceptions {
+			if err := validateException(e); err != nil {
+				return fmt.Errorf("location %d, exception %d: %w", i+1, j+1, err)
+			}
+		}
+		for _, d := range loc.NotifyDaysBefore {
+			if d < 1 || d > 7 {
+				return fmt.Errorf("location %d: notify_days_before must be between 1 and 7", i+1)
+			}
+		}
+		for j := range loc.Reminders {
+			if err := validateReminder(&loc.Reminders[j]); err != nil {
+				return fmt.Errorf("location %d, reminder %d: %w", i+1, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateException checks that an exception has exactly one form and valid dates.
+func validateException(e Exception) error {
+	forms := 0
+	var dates []string
+	if e.Skip != "" {
+		forms++
+		dates = append(dates, e.Skip)
+	}
+	if e.From != "" || e.To != "" {
+		forms++
+		if e.From == "" || e.To == "" {
+			return fmt.Errorf("from and to must be set together")
+		}
+		if e.From == e.To {
+			return fmt.Errorf("from and to must be different dates")
+		}
+		dates = append(dates, e.From, e.To)
+	}
+	if e.Extra != "" {
+		forms++
+		if len(e.Types) == 0 {
+			return fmt.Errorf("extra requires types")
+		}
+		dates = append(dates, e.Extra)
+	}
+	if forms != 1 {
+		return fmt.Errorf("set exactly one of skip, from/to or extra")
+	}
+	for _, d := range dates {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return fmt.Errorf("invalid date %q", d)
+		}
+	}
+	return nil
+}
+
+// locationField returns the value of a location's scraper config field by its
+// YAML name.
+func locationField(loc Location, name string) string {
+	switch name {
+	case "postcode":
+		return loc.PostCode
+	case "address_code":
+		return loc.AddressCode
+	default:
+		return ""
+	}
+}
+
+// validateActiveWindow checks a collection day's active_from and active_until.
+func validateActiveWindow(cd CollectionDay) error {
+	for _, bound := range []struct{ name, value string }{
+		{"active_from", cd.ActiveFrom},
+		{"active_until", cd.ActiveUntil},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		if _, err := time.Parse("01-02", bound.value); err == nil {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", bound.value); err != nil {
+			return fmt.Errorf("%s must be MM-DD or YYYY-MM-DD, got %q", bound.name, bound.value)
+		}
+	}
+	if len(cd.ActiveFrom) == len("2006-01-02") && len(cd.ActiveUntil) == len("2006-01-02") && cd.ActiveFrom > cd.ActiveUntil {
+		return fmt.Errorf("active_from must not be after active_until")
+	}
+	return nil
+}
+
+// validateRRule parses a collection day's recurrence rule, starting from its
+// reference date when one is set.
+func validateRRule(cd *CollectionDay) error {
+	if cd.RawDay != "" || cd.EveryNWeeks != 0 {
+		return fmt.Errorf("rrule cannot be combined with day or every_n_weeks")
+	}
+	if len(cd.Types) == 0 {
+		return fmt.Errorf("types must have at least one entry")
+	}
+	var start time.Time
+	if cd.ReferenceDate != "" {
+		var err error
+		start, err = time.Parse("2006-01-02", cd.ReferenceDate)
+		if err != nil {
+			return fmt.Errorf("invalid reference_date: %w", err)
+		}
+	}
+	rule, err := rrule.Parse(cd.RRule, start)
+	if err != nil {
+		return fmt.Errorf("invalid rrule: %w", err)
+	}
+	cd.Rule = rule
+	return nil
+}
+
+// validateReminder parses the reminder's time of day and fills in its action.
+func validateReminder(r *Reminder) error {
+	if r.Offset < -7 || r.Offset > 0 {
+		return fmt.Errorf("offset must be between -7 and 0")
+	}
+	if r.RawTime != "" {
+		t, err := time.Parse("15:04", r.RawTime)
+		if err != nil {
+			return fmt.Errorf("time must be HH:MM, got %q", r.RawTime)
+		}
+		r.TimeOfDay = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	switch r.Action {
+	case "":
+		r.Action = ActionPutOut
+		if r.Offset == 0 {
+			r.Action = ActionBringIn
+		}
+	case ActionPutOut, ActionBringIn:
+	default:
+		return fmt.Errorf("unknown action %q", r.Action)
 	}
 	return nil
 }
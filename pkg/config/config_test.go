@@ -1,11 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"log"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/stebennett/bin-notifier/pkg/scraper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -220,6 +223,21 @@ locations:
     collection_days: []`,
 			errText: "collection_days must have at least one entry",
 		},
+		{
+			name: "unknown scraper",
+			yaml: `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: reading
+    postcode: "RG1 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]`,
+			errText: `location 1: unknown scraper "reading" (available: bracknell, wokingham, wokingham-chrome)`,
+		},
 		{
 			name: "missing types",
 			yaml: `
@@ -313,6 +331,37 @@ locations:
 	}
 }
 
+func TestLoadConfig_WarnsOnPostcodeOutsideDistricts(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG1 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+  - label: Office
+    scraper: bracknell
+    postcode: "RG121AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), `WARNING: location 1: postcode "RG1 1AB" is outside scraper "bracknell"'s postcode districts (GU47, RG12, RG40, RG42, RG45, SL4, SL5)`)
+	assert.NotContains(t, logs.String(), "location 2")
+}
+
 func TestLoadConfig_FileNotFound(t *testing.T) {
 	_, err := LoadConfig("/nonexistent/config.yaml")
 	assert.Error(t, err)
@@ -418,28 +467,1563 @@ locations:
 	assert.Equal(t, 1, cfg.Locations[0].CollectionDays[0].EveryNWeeks)
 }
 
-func TestParseFlags_ConfigFromFlag(t *testing.T) {
-	flags, err := ParseFlags([]string{"-c", "/path/to/config.yaml"})
+func TestLoadConfig_DefaultsToTwilioChannel(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	cfg, err := LoadConfig(path)
 	assert.NoError(t, err)
-	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
-	assert.False(t, flags.DryRun)
-	assert.Equal(t, "", flags.TodayDate)
+	assert.Equal(t, []ChannelConfig{{Type: "twilio", Name: "twilio"}}, cfg.Channels)
 }
 
-func TestParseFlags_AllFlags(t *testing.T) {
-	flags, err := ParseFlags([]string{"-c", "/path/to/config.yaml", "-x", "-d", "2024-01-15"})
+func TestLoadConfig_Channels(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+channels:
+  - type: Twilio
+    name: sms
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	cfg, err := LoadConfig(path)
 	assert.NoError(t, err)
-	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
-	assert.True(t, flags.DryRun)
-	assert.Equal(t, "2024-01-15", flags.TodayDate)
+	assert.Equal(t, []ChannelConfig{{Type: "twilio", Name: "sms"}}, cfg.Channels)
 }
 
-func TestParseFlags_LongFlags(t *testing.T) {
-	flags, err := ParseFlags([]string{"--config", "/path/to/config.yaml", "--dryrun", "--todaydate", "2024-01-15"})
-	assert.NoError(t, err)
-	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
-	assert.True(t, flags.DryRun)
-	assert.Equal(t, "2024-01-15", flags.TodayDate)
+func TestLoadConfig_InvalidChannels(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels string
+		errText  string
+	}{
+		{
+			name: "missing type",
+			channels: `
+  - name: sms`,
+			errText: "channel 1: type is required",
+		},
+		{
+			name: "unknown type",
+			channels: `
+  - type: pigeon`,
+			errText: `channel 1: unknown type "pigeon"`,
+		},
+		{
+			name: "duplicate name",
+			channels: `
+  - type: twilio
+  - type: twilio`,
+			errText: `channel 2: duplicate name "twilio"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+channels:`+test.channels+`
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.errText)
+		})
+	}
+}
+
+func TestLoadConfig_EmailChannel(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: email
+    email:
+      host: smtp.example.com
+      from: bins@example.com
+      to: ["alice@example.com"]
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_SMTP_USERNAME", "smtp-user")
+	t.Setenv("BN_SMTP_PASSWORD", "smtp-pass")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	email := cfg.Channels[0].Email
+	assert.Equal(t, "email", cfg.Channels[0].Name)
+	assert.Equal(t, "starttls", email.TLS)
+	assert.Equal(t, 587, email.Port)
+	assert.Equal(t, "smtp-user", email.Username)
+	assert.Equal(t, "smtp-pass", email.Password)
+}
+
+func TestLoadConfig_SMTPCredentialsFromEnvOnlyForOneChannel(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: email
+    name: work
+    email:
+      host: smtp.work.example.com
+      from: bins@work.example.com
+      to: ["alice@work.example.com"]
+  - type: email
+    name: home
+    email:
+      host: smtp.home.example.com
+      from: bins@home.example.com
+      to: ["alice@home.example.com"]
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_SMTP_USERNAME", "smtp-user")
+	t.Setenv("BN_SMTP_PASSWORD", "smtp-pass")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	for _, ch := range cfg.Channels {
+		assert.Empty(t, ch.Email.Username, ch.Name)
+		assert.Empty(t, ch.Email.Password, ch.Name)
+	}
+}
+
+func TestLoadConfig_InvalidEmailChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		errText string
+	}{
+		{
+			name: "missing host",
+			email: `
+      from: bins@example.com
+      to: ["alice@example.com"]`,
+			errText: "email host is required",
+		},
+		{
+			name: "invalid tls",
+			email: `
+      host: smtp.example.com
+      tls: sometimes
+      from: bins@example.com
+      to: ["alice@example.com"]`,
+			errText: `invalid email tls mode: "sometimes"`,
+		},
+		{
+			name: "missing from",
+			email: `
+      host: smtp.example.com
+      to: ["alice@example.com"]`,
+			errText: "email from is required",
+		},
+		{
+			name: "missing to",
+			email: `
+      host: smtp.example.com
+      from: bins@example.com`,
+			errText: "email to must have at least one entry",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeConfigFile(t, `
+channels:
+  - type: email
+    email:`+test.email+`
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.errText)
+		})
+	}
+}
+
+func TestLoadConfig_PushChannels(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: ntfy
+    push:
+      url: https://ntfy.example.com/
+      topic: bins
+      priority: 4
+  - type: gotify
+    push:
+      url: https://gotify.example.com
+      token: app-token
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_PUSH_TOKEN", "env-token")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://ntfy.example.com", cfg.Channels[0].Push.URL)
+	assert.Equal(t, "env-token", cfg.Channels[0].Push.Token)
+	assert.Equal(t, "app-token", cfg.Channels[1].Push.Token)
+}
+
+func TestLoadConfig_PushTokenFromEnvOnlyForOneChannel(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: ntfy
+    push:
+      url: https://ntfy.sh
+      topic: bins
+  - type: gotify
+    push:
+      url: https://gotify.example.com
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_PUSH_TOKEN", "secret-gotify")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Channels[0].Push.Token, "a token isn't shared between push channels")
+	assert.Empty(t, cfg.Channels[1].Push.Token)
+}
+
+func TestLoadConfig_InvalidPushChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		errText string
+	}{
+		{
+			name: "missing url",
+			channel: `
+  - type: gotify
+    push:
+      token: abc`,
+			errText: "push url is required",
+		},
+		{
+			name: "ntfy missing topic",
+			channel: `
+  - type: ntfy
+    push:
+      url: https://ntfy.example.com`,
+			errText: "push topic is required for ntfy",
+		},
+		{
+			name: "ntfy priority out of range",
+			channel: `
+  - type: ntfy
+    push:
+      url: https://ntfy.example.com
+      topic: bins
+      priority: 7`,
+			errText: "push priority must be between 0 and 5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeConfigFile(t, `
+channels:`+test.channel+`
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.errText)
+		})
+	}
+}
+
+func TestLoadConfig_TelegramChannel(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: telegram
+    telegram:
+      chat_ids: ["111"]
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+  - label: Office
+    scraper: wokingham
+    postcode: "RG42 2XY"
+    address_code: "67890"
+    telegram_chat_ids: ["222", "333"]
+    collection_days:
+      - day: thursday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_TELEGRAM_TOKEN", "123:abc")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "123:abc", cfg.Channels[0].Telegram.Token)
+	assert.Equal(t, "https://api.telegram.org", cfg.Channels[0].Telegram.BaseURL)
+	assert.Equal(t, []string{"222", "333"}, cfg.Locations[1].TelegramChatIDs)
+}
+
+func TestLoadConfig_TelegramRequiresChatForEveryLocation(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: telegram
+    telegram:
+      token: "123:abc"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `telegram chat_ids is required when location "Home" has no telegram_chat_ids`)
+}
+
+func TestLoadConfig_WebhookChannel(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: webhook
+    webhook:
+      url: https://hooks.example.com/bins
+      headers:
+        X-Api-Key: key
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_WEBHOOK_SECRET", "s3cret")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	webhook := cfg.Channels[0].Webhook
+	assert.Equal(t, "https://hooks.example.com/bins", webhook.URL)
+	assert.Equal(t, map[string]string{"X-Api-Key": "key"}, webhook.Headers)
+	assert.Equal(t, "s3cret", webhook.Secret)
+}
+
+func TestLoadConfig_WebhookRequiresURL(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: webhook
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "channel 1: webhook url is required")
+}
+
+func TestLoadConfig_SlackAndDiscordChannels(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: slack
+    slack:
+      webhook_url: https://hooks.slack.com/services/T/B/X
+  - type: discord
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    color: "#1E88E5"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_DISCORD_WEBHOOK_URL", "https://discord.com/api/webhooks/1/abc")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/services/T/B/X", cfg.Channels[0].Slack.WebhookURL)
+	assert.Equal(t, "https://discord.com/api/webhooks/1/abc", cfg.Channels[1].Discord.WebhookURL)
+	assert.Equal(t, "#1E88E5", cfg.Locations[0].Color)
+}
+
+func TestLoadConfig_SlackRequiresWebhookURL(t *testing.T) {
+	path := writeConfigFile(t, `
+channels:
+  - type: slack
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_SLACK_WEBHOOK_URL", "")
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "channel 1: slack webhook_url is required")
+}
+
+func TestLoadConfig_InvalidLocationColor(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    color: green
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "location 1: color must be a hex colour")
+}
+
+func TestLoadConfig_Templates(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+templates:
+  collection: "{{.Label}}: {{join .Types \" & \"}}"
+channels:
+  - type: twilio
+    templates:
+      schedule_warning: "{{.Label}}: nothing booked for {{.Weekday}}"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    templates:
+      collection: |
+        {{range .Types}}{{emoji .}} {{title .}}
+        {{end}}
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{{.Label}}: {{join .Types " & "}}`, cfg.Templates.Collection)
+	assert.Equal(t, "{{.Label}}: nothing booked for {{.Weekday}}", cfg.Channels[0].Templates.ScheduleWarning)
+	assert.Contains(t, cfg.Locations[0].Templates.Collection, "{{emoji .}}")
+}
+
+func TestLoadConfig_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "global syntax error",
+			yaml: `
+templates:
+  collection: "{{.Label"
+`,
+			wantErr: "templates: collection: invalid template",
+		},
+		{
+			name: "channel unknown field",
+			yaml: `
+channels:
+  - type: twilio
+    templates:
+      schedule_warning: "{{.Lable}}"
+`,
+			wantErr: "channel 1: schedule_warning: invalid template",
+		},
+		{
+			name: "unknown function",
+			yaml: `
+templates:
+  schedule_warning: "{{upper .Label}}"
+`,
+			wantErr: "templates: schedule_warning: invalid template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`+tt.yaml)
+
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_InvalidLocationTemplate(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    templates:
+      collection: "{{.Label}} {{.Tomorrow}}"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "location 1: collection: invalid template")
+}
+
+func TestLoadConfig_Recipients(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+channels:
+  - type: twilio
+  - type: email
+    email:
+      host: smtp.example.com
+      from: bins@example.com
+recipients:
+  - channel: email
+    address: me@example.com
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    recipients:
+      - channel: twilio
+        address: "+447700900001"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+  - label: Office
+    scraper: wokingham
+    postcode: "RG40 1AA"
+    address_code: "67890"
+    recipients:
+      - channel: twilio
+        address: "+447700900002"
+        types: ["Recycling"]
+    collection_days:
+      - day: friday
+        types: ["Recycling"]
+`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ToNumber)
+	assert.Equal(t, []Recipient{{Channel: "email", Address: "me@example.com"}}, cfg.Recipients)
+	assert.Equal(t, []Recipient{{Channel: "twilio", Address: "+447700900001"}}, cfg.Locations[0].Recipients)
+	assert.Equal(t, []Recipient{{Channel: "twilio", Address: "+447700900002", Types: []string{"Recycling"}}}, cfg.Locations[1].Recipients)
+}
+
+func TestLoadConfig_ToNumberRequiredWhenLocationHasNoRecipients(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    recipients:
+      - channel: twilio
+        address: "+447700900001"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+  - label: Office
+    scraper: wokingham
+    postcode: "RG40 1AA"
+    address_code: "67890"
+    collection_days:
+      - day: friday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "to_number is required")
+
+	t.Setenv("BN_TO_NUMBER", "+449876543210")
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "+449876543210", cfg.ToNumber)
+}
+
+func TestLoadConfig_InvalidRecipients(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "missing channel",
+			yaml: `
+recipients:
+  - address: "+447700900001"
+`,
+			wantErr: "recipient 1: channel is required",
+		},
+		{
+			name: "unknown channel",
+			yaml: `
+recipients:
+  - channel: sms
+    address: "+447700900001"
+`,
+			wantErr: `recipient 1: unknown channel "sms"`,
+		},
+		{
+			name: "missing address",
+			yaml: `
+recipients:
+  - channel: twilio
+`,
+			wantErr: "recipient 1: address is required",
+		},
+		{
+			name: "channel without addresses",
+			yaml: `
+channels:
+  - type: twilio
+  - type: ntfy
+    push:
+      url: https://ntfy.sh
+      topic: bins
+recipients:
+  - channel: ntfy
+    address: other-topic
+`,
+			wantErr: `recipient 1: channel "ntfy" does not support recipients`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`+tt.yaml)
+
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_InvalidLocationRecipient(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    recipients:
+      - channel: twilio
+        address: "+447700900001"
+      - channel: email
+        address: me@example.com
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `location 1, recipient 2: unknown channel "email"`)
+}
+
+func TestLoadConfig_Digest(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+digest: true
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Digest)
+}
+
+func TestLoadConfig_RetryDefaults(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, RetryConfig{MaxAttempts: 3, InitialDelay: 2 * time.Second, MaxDelay: 30 * time.Second}, cfg.Retry)
+}
+
+func TestLoadConfig_Retry(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, base+`
+retry:
+  max_attempts: 5
+  initial_delay: 500ms
+  max_delay: 1m
+`))
+	require.NoError(t, err)
+	assert.Equal(t, RetryConfig{MaxAttempts: 5, InitialDelay: 500 * time.Millisecond, MaxDelay: time.Minute}, cfg.Retry)
+
+	_, err = LoadConfig(writeConfigFile(t, base+`
+retry:
+  max_attempts: -1
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "retry: values must not be negative")
+
+	_, err = LoadConfig(writeConfigFile(t, base+`
+retry:
+  initial_delay: 1m
+  max_delay: 10s
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "retry: max_delay must be at least initial_delay")
+}
+
+func TestLoadConfig_MQTTDefaults(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+mqtt:
+  broker: tcp://localhost:1883
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	t.Setenv("BN_MQTT_USERNAME", "mqtt-user")
+	t.Setenv("BN_MQTT_PASSWORD", "mqtt-pass")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, MQTTConfig{
+		Broker:          "tcp://localhost:1883",
+		Username:        "mqtt-user",
+		Password:        "mqtt-pass",
+		ClientID:        "bin-notifier",
+		DiscoveryPrefix: "homeassistant",
+		BaseTopic:       "bin-notifier",
+	}, cfg.MQTT)
+}
+
+func TestLoadConfig_TimezoneDefaultsToLondon(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/London", cfg.Timezone)
+	require.NotNil(t, cfg.Zone)
+	assert.Equal(t, "Europe/London", cfg.Zone.String())
+}
+
+func TestLoadConfig_Timezone(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, "timezone: Europe/Dublin\n"+base))
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Dublin", cfg.Zone.String())
+
+	t.Setenv("BN_TIMEZONE", "UTC")
+	cfg, err = LoadConfig(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, cfg.Zone)
+
+	cfg, err = LoadConfigForMCP(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, cfg.Zone)
+
+	_, err = LoadConfig(writeConfigFile(t, "timezone: Mars/Olympus\n"+base))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `timezone: unknown time zone "Mars/Olympus"`)
+}
+
+func TestLoadConfig_Daemon(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, DaemonConfig{Schedule: "0 18 * * *", CacheTTL: 6 * time.Hour}, cfg.Daemon)
+
+	cfg, err = LoadConfig(writeConfigFile(t, base+`
+daemon:
+  schedule: "0 * * * *"
+  cache_ttl: 2h
+`))
+	require.NoError(t, err)
+	assert.Equal(t, DaemonConfig{Schedule: "0 * * * *", CacheTTL: 2 * time.Hour}, cfg.Daemon)
+
+	t.Setenv("BN_SCHEDULE", "30 19 * * *")
+	cfg, err = LoadConfig(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, "30 19 * * *", cfg.Daemon.Schedule)
+
+	_, err = LoadConfig(writeConfigFile(t, base+`
+daemon:
+  schedule: "at teatime"
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `daemon: invalid schedule "at teatime"`)
+
+	_, err = LoadConfig(writeConfigFile(t, base+`
+daemon:
+  cache_ttl: -1h
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "daemon: cache_ttl must not be negative")
+}
+
+func TestLoadConfig_Holidays(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, "england-and-wales", cfg.Holidays.Calendar)
+	assert.True(t, cfg.HolidayCalendar.IsHoliday(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, HolidayShift{Scope: HolidayScopeWeek}, cfg.Locations[0].HolidayShift)
+
+	cfg, err = LoadConfig(writeConfigFile(t, base+`    holiday_shift:
+      days: 1
+      scope: day
+holidays:
+  calendar: none
+  dates: ["2026-06-01"]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, HolidayShift{Days: 1, Scope: HolidayScopeDay}, cfg.Locations[0].HolidayShift)
+	assert.False(t, cfg.HolidayCalendar.IsHoliday(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, cfg.HolidayCalendar.IsHoliday(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestLoadConfig_InvalidHolidays(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	tests := []struct {
+		name   string
+		extra  string
+		errMsg string
+	}{
+		{"unknown calendar", "holidays:\n  calendar: mars\n", `holidays: unknown holiday calendar: "mars"`},
+		{"bad date", "holidays:\n  dates: [\"25/12/2026\"]\n", `holidays: date 1: invalid date "25/12/2026"`},
+		{"shift too far", "    holiday_shift:\n      days: 7\n", "location 1: holiday_shift days must be between 0 and 6"},
+		{"unknown scope", "    holiday_shift:\n      days: 1\n      scope: month\n", `location 1: unknown holiday_shift scope "month"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, base+tt.extra))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfig_RRule(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+      - rrule: "FREQ=MONTHLY;BYDAY=1MO"
+        types: ["Bulky Waste"]
+      - rrule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE;BYMONTH=3,4,5,6,7,8,9,10,11"
+        reference_date: "2026-03-04"
+        types: ["Garden Waste"]
+`)
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	days := cfg.Locations[0].CollectionDays
+	assert.Nil(t, days[0].Rule)
+	require.NotNil(t, days[1].Rule)
+	assert.True(t, days[1].Rule.Occurs(time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)))
+	require.NotNil(t, days[2].Rule)
+	assert.True(t, days[2].Rule.Occurs(time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, days[2].Rule.Occurs(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestLoadConfig_InvalidRRule(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+`
+	tests := []struct {
+		name   string
+		extra  string
+		errMsg string
+	}{
+		{"with day", "      - rrule: \"FREQ=WEEKLY;BYDAY=MO\"\n        day: monday\n        types: [\"Recycling\"]\n", "location 1, schedule 1: rrule cannot be combined with day or every_n_weeks"},
+		{"with every_n_weeks", "      - rrule: \"FREQ=WEEKLY;BYDAY=MO\"\n        every_n_weeks: 2\n        types: [\"Recycling\"]\n", "location 1, schedule 1: rrule cannot be combined with day or every_n_weeks"},
+		{"missing types", "      - rrule: \"FREQ=WEEKLY;BYDAY=MO\"\n", "location 1, schedule 1: types must have at least one entry"},
+		{"bad reference_date", "      - rrule: \"FREQ=WEEKLY;BYDAY=MO\"\n        reference_date: \"soon\"\n        types: [\"Recycling\"]\n", "location 1, schedule 1: invalid reference_date"},
+		{"bad rule", "      - rrule: \"FREQ=HOURLY\"\n        types: [\"Recycling\"]\n", `location 1, schedule 1: invalid rrule: unsupported FREQ "HOURLY"`},
+		{"interval without start", "      - rrule: \"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO\"\n        types: [\"Recycling\"]\n", "location 1, schedule 1: invalid rrule: INTERVAL above 1 needs a start date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, base+tt.extra))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfig_ActiveWindow(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: monday
+        types: ["Garden Waste"]
+        active_from: "03-01"
+        active_until: "11-30"
+      - rrule: "FREQ=MONTHLY;BYDAY=1MO"
+        types: ["Bulky Waste"]
+        active_until: "2026-12-31"
+`)
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	days := cfg.Locations[0].CollectionDays
+	assert.Equal(t, "03-01", days[0].ActiveFrom)
+	assert.Equal(t, "11-30", days[0].ActiveUntil)
+	assert.Equal(t, "2026-12-31", days[1].ActiveUntil)
+}
+
+func TestLoadConfig_InvalidActiveWindow(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: monday
+        types: ["Garden Waste"]
+`
+	tests := []struct {
+		name   string
+		extra  string
+		errMsg string
+	}{
+		{"bad month-day", "        active_from: \"3-1\"\n", `location 1, schedule 1: active_from must be MM-DD or YYYY-MM-DD, got "3-1"`},
+		{"bad date", "        active_until: \"2026-13-01\"\n", `location 1, schedule 1: active_until must be MM-DD or YYYY-MM-DD, got "2026-13-01"`},
+		{"reversed dates", "        active_from: \"2026-11-30\"\n        active_until: \"2026-03-01\"\n", "location 1, schedule 1: active_from must not be after active_until"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, base+tt.extra))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfig_Browser(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Browser.MaxTabs)
+
+	cfg, err = LoadConfig(writeConfigFile(t, base+"browser:\n  max_tabs: 4\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Browser.MaxTabs)
+
+	cfg, err = LoadConfigForMCP(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Browser.MaxTabs)
+
+	_, err = LoadConfig(writeConfigFile(t, base+"browser:\n  max_tabs: -1\n"))
+	assert.EqualError(t, err, "browser: max_tabs must not be negative")
+}
+
+// writeRecipe writes a scraper recipe named name into dir.
+func writeRecipe(t *testing.T, dir, name string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(`
+name: `+name+`
+display_name: Testshire District Council
+url: https://bins.testshire.gov.uk/lookup?postcode={postcode}
+extract:
+  selector: li
+  pattern: '(?P<BinType>[A-Za-z ]+): (?P<Date>\d{2}/\d{2}/\d{4})'
+  date_layout: 02/01/2006
+`), 0644))
+}
+
+func TestLoadConfig_ScrapersDir(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+scrapers_dir: scrapers
+locations:
+  - label: Home
+    scraper: testshire
+    postcode: "TE1 1AA"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	dir := filepath.Join(filepath.Dir(path), "scrapers")
+	writeRecipe(t, dir, "testshire")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, dir, cfg.ScrapersDir)
+	require.Len(t, cfg.Recipes, 1)
+	assert.Equal(t, "Testshire District Council", cfg.Recipes[0].DisplayName)
+	_, ok := scraper.Lookup("testshire")
+	assert.False(t, ok, "loading a config doesn't register its recipes")
+
+	// Loading again, as the MCP server does, gives the same recipes.
+	cfg, err = LoadConfigForMCP(path)
+	require.NoError(t, err)
+	assert.Equal(t, "testshire", cfg.Locations[0].Scraper)
+	require.Len(t, cfg.Recipes, 1)
+
+	// Recipes from one config aren't available to another.
+	_, err = LoadConfig(writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: testshire
+    postcode: "TE1 1AA"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`))
+	assert.EqualError(t, err, `location 1: unknown scraper "testshire" (available: bracknell, wokingham, wokingham-chrome)`)
+}
+
+func TestLoadConfig_ScrapersDirErrors(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+scrapers_dir: scrapers
+locations:
+  - label: Home
+    scraper: reading
+    postcode: "RG1 1AA"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`)
+	dir := filepath.Join(filepath.Dir(path), "scrapers")
+	writeRecipe(t, dir, "testshire")
+
+	_, err := LoadConfig(path)
+	assert.EqualError(t, err, `location 1: unknown scraper "reading" (available: bracknell, testshire, wokingham, wokingham-chrome)`)
+
+	writeRecipe(t, dir, "bracknell")
+	_, err = LoadConfig(path)
+	assert.EqualError(t, err, `scrapers_dir: recipe "bracknell": a built-in scraper has that name`)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bracknell.yaml"), []byte("name: broken\n"), 0644))
+	_, err = LoadConfig(path)
+	assert.EqualError(t, err, "scrapers_dir: "+filepath.Join(dir, "bracknell.yaml")+": url is required")
+
+	_, err = LoadConfig(writeConfigFile(t, "scrapers_dir: /does/not/exist\n"))
+	assert.ErrorContains(t, err, "scrapers_dir: open /does/not/exist")
+}
+
+func TestLoadRecipes(t *testing.T) {
+	// The rest of the config isn't validated.
+	path := writeConfigFile(t, "scrapers_dir: scrapers\nlocations: []\n")
+	writeRecipe(t, filepath.Join(filepath.Dir(path), "scrapers"), "testshire")
+
+	recipes, err := LoadRecipes(path)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "testshire", recipes[0].Name)
+
+	envDir := t.TempDir()
+	writeRecipe(t, envDir, "envshire")
+	t.Setenv("BN_SCRAPERS_DIR", envDir)
+	recipes, err = LoadRecipes("")
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "envshire", recipes[0].Name)
+
+	recipes, err = LoadRecipes(path)
+	require.NoError(t, err)
+	assert.Equal(t, "testshire", recipes[0].Name, "scrapers_dir takes precedence")
+
+	t.Setenv("BN_SCRAPERS_DIR", "")
+	recipes, err = LoadRecipes("")
+	assert.NoError(t, err)
+	assert.Empty(t, recipes)
+}
+
+func TestLoadConfig_ScrapeTimeout(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, base))
+	require.NoError(t, err)
+	assert.Equal(t, 60*time.Second, cfg.Locations[0].ScrapeTimeout)
+
+	cfg, err = LoadConfig(writeConfigFile(t, base+"    scrape_timeout: 2m\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.Locations[0].ScrapeTimeout)
+
+	_, err = LoadConfig(writeConfigFile(t, base+"    scrape_timeout: -1s\n"))
+	assert.EqualError(t, err, "location 1: scrape_timeout must not be negative")
+}
+
+func TestLoadConfig_Exceptions(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: friday
+        types: ["Recycling"]
+    exceptions:
+      - skip: "2027-01-01"
+      - from: "2026-12-25"
+        to: "2026-12-28"
+        types: ["Recycling"]
+      - extra: "2027-01-09"
+        types: ["Christmas Trees"]
+`)
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Exception{
+		{Skip: "2027-01-01"},
+		{From: "2026-12-25", To: "2026-12-28", Types: []string{"Recycling"}},
+		{Extra: "2027-01-09", Types: []string{"Christmas Trees"}},
+	}, cfg.Locations[0].Exceptions)
+}
+
+func TestLoadConfig_InvalidExceptions(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: friday
+        types: ["Recycling"]
+    exceptions:
+`
+	tests := []struct {
+		name   string
+		extra  string
+		errMsg string
+	}{
+		{"empty", "      - types: [\"Recycling\"]\n", "location 1, exception 1: set exactly one of skip, from/to or extra"},
+		{"two forms", "      - skip: \"2027-01-01\"\n        extra: \"2027-01-02\"\n        types: [\"Recycling\"]\n", "location 1, exception 1: set exactly one of skip, from/to or extra"},
+		{"from without to", "      - from: \"2026-12-25\"\n", "location 1, exception 1: from and to must be set together"},
+		{"same date", "      - from: \"2026-12-25\"\n        to: \"2026-12-25\"\n", "location 1, exception 1: from and to must be different dates"},
+		{"extra without types", "      - extra: \"2027-01-09\"\n", "location 1, exception 1: extra requires types"},
+		{"bad date", "      - skip: \"2027-01-01\"\n      - skip: \"01/01/2027\"\n", `location 1, exception 2: invalid date "01/01/2027"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, base+tt.extra))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfig_Reminders(t *testing.T) {
+	path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+    reminders:
+      - offset: -1
+        time: "19:00"
+      - offset: 0
+        time: "17:30"
+      - offset: 0
+        time: "06:00"
+        action: put_out
+`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Reminder{
+		{Offset: -1, RawTime: "19:00", Action: ActionPutOut, TimeOfDay: 19 * time.Hour},
+		{Offset: 0, RawTime: "17:30", Action: ActionBringIn, TimeOfDay: 17*time.Hour + 30*time.Minute},
+		{Offset: 0, RawTime: "06:00", Action: ActionPutOut, TimeOfDay: 6 * time.Hour},
+	}, cfg.Locations[0].Reminders)
+}
+
+func TestLoadConfig_NotifyDaysBefore(t *testing.T) {
+	base := `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Garden Waste"]
+`
+	cfg, err := LoadConfig(writeConfigFile(t, base+`    notify_days_before: [1, 2]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, cfg.Locations[0].NotifyDaysBefore)
+
+	_, err = LoadConfig(writeConfigFile(t, base+`    notify_days_before: [0]
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "location 1: notify_days_before must be between 1 and 7")
+}
+
+func TestLoadConfig_InvalidReminders(t *testing.T) {
+	tests := []struct {
+		name     string
+		reminder string
+		errMsg   string
+	}{
+		{"offset after collection", "offset: 1", "location 1, reminder 1: offset must be between -7 and 0"},
+		{"offset too early", "offset: -8", "location 1, reminder 1: offset must be between -7 and 0"},
+		{"bad time", `time: "7pm"`, `location 1, reminder 1: time must be HH:MM, got "7pm"`},
+		{"unknown action", "action: wheel_out", `location 1, reminder 1: unknown action "wheel_out"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, `
+from_number: "+441234567890"
+to_number: "+449876543210"
+locations:
+  - label: Home
+    scraper: bracknell
+    postcode: "RG12 1AB"
+    address_code: "12345"
+    collection_days:
+      - day: tuesday
+        types: ["Recycling"]
+    reminders:
+      - `+tt.reminder+`
+`)
+
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestParseFlags_ConfigFromFlag(t *testing.T) {
+	flags, err := ParseFlags([]string{"-c", "/path/to/config.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
+	assert.False(t, flags.DryRun)
+	assert.Equal(t, "", flags.TodayDate)
+}
+
+func TestParseFlags_AllFlags(t *testing.T) {
+	flags, err := ParseFlags([]string{"-c", "/path/to/config.yaml", "-x", "-d", "2024-01-15"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
+	assert.True(t, flags.DryRun)
+	assert.Equal(t, "2024-01-15", flags.TodayDate)
+}
+
+func TestParseFlags_LongFlags(t *testing.T) {
+	flags, err := ParseFlags([]string{"--config", "/path/to/config.yaml", "--dryrun", "--todaydate", "2024-01-15"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
+	assert.True(t, flags.DryRun)
+	assert.Equal(t, "2024-01-15", flags.TodayDate)
+}
+
+func TestParseFlags_Serve(t *testing.T) {
+	flags, err := ParseFlags([]string{"serve", "-c", "/path/to/config.yaml", "-s", "state.json"})
+	assert.NoError(t, err)
+	assert.True(t, flags.Serve)
+	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
+	assert.Equal(t, "state.json", flags.StateFile)
+
+	flags, err = ParseFlags([]string{"-c", "/path/to/config.yaml"})
+	assert.NoError(t, err)
+	assert.False(t, flags.Serve)
+}
+
+func TestParseFlags_Scrapers(t *testing.T) {
+	t.Setenv("BN_CONFIG_FILE", "")
+	flags, err := ParseFlags([]string{"scrapers"})
+	assert.NoError(t, err)
+	assert.True(t, flags.ListScrapers)
+	assert.Empty(t, flags.ConfigFile)
+
+	flags, err = ParseFlags([]string{"scrapers", "-c", "/path/to/config.yaml"})
+	assert.NoError(t, err)
+	assert.True(t, flags.ListScrapers)
+	assert.Equal(t, "/path/to/config.yaml", flags.ConfigFile)
+}
+
+func TestParseFlags_HistoryFlags(t *testing.T) {
+	flags, err := ParseFlags([]string{"-c", "/path/to/config.yaml", "-s", "/var/lib/bins/state.json", "-f"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/lib/bins/state.json", flags.StateFile)
+	assert.True(t, flags.Force)
+
+	flags, err = ParseFlags([]string{"-c", "/path/to/config.yaml", "--statefile", "state.json", "--force"})
+	assert.NoError(t, err)
+	assert.Equal(t, "state.json", flags.StateFile)
+	assert.True(t, flags.Force)
+}
+
+func TestParseFlags_HistoryFlagsFromEnv(t *testing.T) {
+	t.Setenv("BN_CONFIG_FILE", "/env/config.yaml")
+	t.Setenv("BN_STATE_FILE", "/env/state.json")
+	t.Setenv("BN_FORCE", "true")
+	flags, err := ParseFlags([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "/env/state.json", flags.StateFile)
+	assert.True(t, flags.Force)
 }
 
 func TestParseFlags_MissingConfigReturnsError(t *testing.T) {
@@ -6,21 +6,31 @@ import (
 	"time"
 )
 
-func AsTime(day, month, year int) time.Time {
-	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+// AsTime returns midnight on the given date in loc.
+func AsTime(day, month, year int, loc *time.Location) time.Time {
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
 }
 
-func AsTimeWithMonth(day int, month string, year int) time.Time {
+// AsTimeWithMonth is AsTime with the month given by name, e.g. "February".
+func AsTimeWithMonth(day int, month string, year int, loc *time.Location) time.Time {
 	dt, _ := time.Parse("January", month)
-	return time.Date(year, dt.Month(), day, 0, 0, 0, 0, time.UTC)
+	return time.Date(year, dt.Month(), day, 0, 0, 0, 0, loc)
+}
+
+// Today returns midnight in loc on the day that now falls on in loc.
+func Today(now time.Time, loc *time.Location) time.Time {
+	y, m, d := now.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
 }
 
 func IsDateMatching(t1, t2 time.Time) bool {
 	return t1.Year() == t2.Year() && t1.YearDay() == t2.YearDay()
 }
 
+// normalizeToUTCMidnight maps t's calendar date in its own location to UTC
+// midnight, so days can be counted without DST changes skewing the hours.
 func normalizeToUTCMidnight(t time.Time) time.Time {
-	y, m, d := t.In(time.UTC).Date()
+	y, m, d := t.Date()
 	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
@@ -38,6 +48,27 @@ func IsOnWeek(referenceDate, targetDate time.Time, everyNWeeks int) bool {
 	return weeks%everyNWeeks == 0
 }
 
+// DaysBetween returns the number of calendar days from one date to another,
+// ignoring the time of day. It is negative when to is before from.
+func DaysBetween(from, to time.Time) int {
+	f := normalizeToUTCMidnight(from)
+	t := normalizeToUTCMidnight(to)
+	return int(t.Sub(f).Hours() / 24)
+}
+
+// RelativeDay describes a date the given number of days away: "today",
+// "tomorrow" or "in 3 days".
+func RelativeDay(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
 func ParseWeekday(s string) (time.Weekday, error) {
 	days := map[string]time.Weekday{
 		"sunday":    time.Sunday,
@@ -47,7 +47,7 @@ func TestAsTime(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual := AsTime(test.day, test.month, test.year)
+			actual := AsTime(test.day, test.month, test.year, time.UTC)
 			assert.Equal(t, test.expected, actual)
 		})
 	}
@@ -100,12 +100,77 @@ func TestAsTimeWithMonth(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual := AsTimeWithMonth(test.day, test.month, test.year)
+			actual := AsTimeWithMonth(test.day, test.month, test.year, time.UTC)
 			assert.Equal(t, test.expected, actual)
 		})
 	}
 }
 
+func TestAsTime_InLocation(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summer := AsTime(16, 6, 2026, london)
+	assert.Equal(t, time.Date(2026, time.June, 15, 23, 0, 0, 0, time.UTC), summer.UTC())
+	assert.Equal(t, AsTime(16, 6, 2026, london), AsTimeWithMonth(16, "June", 2026, london))
+}
+
+func TestToday_DSTTransitions(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "after midnight BST is already the next day",
+			now:      time.Date(2026, time.June, 15, 23, 30, 0, 0, time.UTC), // 00:30 BST on the 16th
+			expected: time.Date(2026, time.June, 16, 0, 0, 0, 0, london),
+		},
+		{
+			name:     "winter matches UTC",
+			now:      time.Date(2026, time.January, 15, 23, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, time.January, 15, 0, 0, 0, 0, london),
+		},
+		{
+			name:     "clocks go forward",
+			now:      time.Date(2026, time.March, 29, 1, 30, 0, 0, time.UTC), // 02:30 BST
+			expected: time.Date(2026, time.March, 29, 0, 0, 0, 0, london),
+		},
+		{
+			name:     "clocks go back",
+			now:      time.Date(2026, time.October, 24, 23, 30, 0, 0, time.UTC), // 00:30 BST on the 25th
+			expected: time.Date(2026, time.October, 25, 0, 0, 0, 0, london),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Today(test.now, london))
+		})
+	}
+}
+
+func TestDaysBetween_AcrossDSTChange(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The night the clocks go forward is only 23 hours long.
+	from := time.Date(2026, time.March, 28, 0, 0, 0, 0, london)
+	to := time.Date(2026, time.March, 30, 0, 0, 0, 0, london)
+
+	assert.Equal(t, 2, DaysBetween(from, to))
+	assert.True(t, IsOnWeek(time.Date(2026, time.March, 27, 0, 0, 0, 0, time.UTC), time.Date(2026, time.April, 10, 0, 0, 0, 0, london), 2))
+}
+
 func TestIsDateMatching(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -261,6 +326,52 @@ func TestIsOnWeek(t *testing.T) {
 	}
 }
 
+func TestDaysBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		expected int
+	}{
+		{
+			name:     "same day",
+			from:     time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC),
+			to:       time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		{
+			name:     "tomorrow",
+			from:     time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC),
+			to:       time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+			expected: 1,
+		},
+		{
+			name:     "across month boundary",
+			from:     time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+			expected: 3,
+		},
+		{
+			name:     "in the past",
+			from:     time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC),
+			expected: -2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DaysBetween(test.from, test.to))
+		})
+	}
+}
+
+func TestRelativeDay(t *testing.T) {
+	assert.Equal(t, "today", RelativeDay(0))
+	assert.Equal(t, "tomorrow", RelativeDay(1))
+	assert.Equal(t, "in 2 days", RelativeDay(2))
+}
+
 func TestParseWeekday(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,162 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retention is how long delivered notifications are remembered.
+const retention = 90 * 24 * time.Hour
+
+// Notification identifies one message delivered to one recipient. Recipient is
+// empty when the channel's own configured addresses were used.
+type Notification struct {
+	Location  string
+	Date      time.Time
+	Kind      string
+	Types     []string
+	Channel   string
+	Recipient string
+}
+
+func (n Notification) key() string {
+	types := append([]string(nil), n.Types...)
+	sort.Strings(types)
+	return strings.Join([]string{
+		n.Location,
+		n.Date.Format("2006-01-02"),
+		n.Kind,
+		strings.Join(types, ","),
+		n.Channel,
+		n.Recipient,
+	}, "|")
+}
+
+// Entry is a delivered notification as persisted in the state file.
+type Entry struct {
+	Location  string    `json:"location"`
+	Date      string    `json:"date"`
+	Kind      string    `json:"kind"`
+	Types     []string  `json:"types"`
+	Channel   string    `json:"channel"`
+	Recipient string    `json:"recipient,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+type stateFile struct {
+	Notifications []Entry `json:"notifications"`
+}
+
+// FileStore is a notification history persisted as a JSON file.
+type FileStore struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// Open loads the history at path. A missing file is treated as an empty history.
+func Open(path string) (*FileStore, error) {
+	s := &FileStore{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	for _, e := range state.Notifications {
+		date, err := time.Parse("2006-01-02", e.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+		}
+		n := Notification{
+			Location:  e.Location,
+			Date:      date,
+			Kind:      e.Kind,
+			Types:     e.Types,
+			Channel:   e.Channel,
+			Recipient: e.Recipient,
+		}
+		s.entries[n.key()] = e
+	}
+	return s, nil
+}
+
+// Sent reports whether n has already been delivered.
+func (s *FileStore) Sent(n Notification) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.entries[n.key()]
+	return ok
+}
+
+// Record marks n as delivered at the given time and saves the history, dropping
+// entries older than the retention period.
+func (s *FileStore) Record(n Notification, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[n.key()] = Entry{
+		Location:  n.Location,
+		Date:      n.Date.Format("2006-01-02"),
+		Kind:      n.Kind,
+		Types:     n.Types,
+		Channel:   n.Channel,
+		Recipient: n.Recipient,
+		SentAt:    at,
+	}
+	for k, e := range s.entries {
+		if at.Sub(e.SentAt) > retention {
+			delete(s.entries, k)
+		}
+	}
+	return s.save()
+}
+
+// save writes the history to a temporary file and renames it into place so an
+// interrupted run never leaves a truncated state file.
+func (s *FileStore) save() error {
+	keys := make([]string, 0, len(s.entries))
+	for k := range s.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	state := stateFile{Notifications: make([]Entry, 0, len(keys))}
+	for _, k := range keys {
+		state.Notifications = append(state.Notifications, s.entries[k])
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNotification() Notification {
+	return Notification{
+		Location:  "Home",
+		Date:      time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+		Kind:      "collection",
+		Types:     []string{"Recycling", "General Waste"},
+		Channel:   "twilio",
+		Recipient: "+447700900001",
+	}
+}
+
+func TestOpen_MissingFileIsEmpty(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "state.json"))
+
+	require.NoError(t, err)
+	assert.False(t, s.Sent(testNotification()))
+}
+
+func TestRecord_PersistsAcrossOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	err = s.Record(testNotification(), time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, s.Sent(testNotification()))
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	assert.True(t, reopened.Sent(testNotification()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"location": "Home"`)
+	assert.Contains(t, string(data), `"date": "2026-01-16"`)
+	assert.Contains(t, string(data), `"sent_at": "2026-01-15T18:00:00Z"`)
+}
+
+func TestSent_KeyedByLocationDateKindTypesAndRecipient(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+	require.NoError(t, s.Record(testNotification(), time.Now()))
+
+	reordered := testNotification()
+	reordered.Types = []string{"General Waste", "Recycling"}
+	assert.True(t, s.Sent(reordered))
+
+	variants := map[string]func(n *Notification){
+		"location":  func(n *Notification) { n.Location = "Office" },
+		"date":      func(n *Notification) { n.Date = n.Date.AddDate(0, 0, 7) },
+		"kind":      func(n *Notification) { n.Kind = "schedule_warning" },
+		"types":     func(n *Notification) { n.Types = []string{"Garden Waste"} },
+		"channel":   func(n *Notification) { n.Channel = "email" },
+		"recipient": func(n *Notification) { n.Recipient = "" },
+	}
+	for name, change := range variants {
+		t.Run(name, func(t *testing.T) {
+			n := testNotification()
+			change(&n)
+			assert.False(t, s.Sent(n))
+		})
+	}
+}
+
+func TestRecord_DropsExpiredEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	old := testNotification()
+	old.Date = time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.Record(old, time.Date(2025, 8, 31, 18, 0, 0, 0, time.UTC)))
+	require.NoError(t, s.Record(testNotification(), time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC)))
+
+	assert.False(t, s.Sent(old))
+	assert.True(t, s.Sent(testNotification()))
+}
+
+func TestOpen_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	_, err := Open(path)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid state file")
+}
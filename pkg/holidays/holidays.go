@@ -0,0 +1,59 @@
+package holidays
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar is a set of holiday dates. A nil Calendar has no holidays.
+type Calendar struct {
+	dates map[string]bool
+	// until is the last year a bundled calendar lists, or 0 for no limit.
+	until int
+}
+
+// New creates a Calendar containing the given dates.
+func New(dates ...time.Time) *Calendar {
+	c := &Calendar{dates: make(map[string]bool)}
+	for _, d := range dates {
+		c.Add(d)
+	}
+	return c
+}
+
+// Named returns a copy of a bundled calendar: "england-and-wales" or "none".
+func Named(name string) (*Calendar, error) {
+	switch name {
+	case "england-and-wales":
+		c := New()
+		for _, d := range englandAndWales {
+			c.dates[d] = true
+		}
+		c.until = englandAndWalesUntil
+		return c, nil
+	case "none":
+		return New(), nil
+	default:
+		return nil, fmt.Errorf("unknown holiday calendar: %q", name)
+	}
+}
+
+// Add marks the calendar day of date, in its own location, as a holiday.
+func (c *Calendar) Add(date time.Time) {
+	c.dates[date.Format("2006-01-02")] = true
+}
+
+// Covers reports whether the calendar lists the holidays of date's year. A
+// bundled calendar stops at the last year it was published for; a nil or
+// custom Calendar covers every year.
+func (c *Calendar) Covers(date time.Time) bool {
+	return c == nil || c.until == 0 || date.Year() <= c.until
+}
+
+// IsHoliday reports whether date falls on a holiday.
+func (c *Calendar) IsHoliday(date time.Time) bool {
+	if c == nil {
+		return false
+	}
+	return c.dates[date.Format("2006-01-02")]
+}
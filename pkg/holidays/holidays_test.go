@@ -0,0 +1,70 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamed_EnglandAndWales(t *testing.T) {
+	c, err := Named("england-and-wales")
+	require.NoError(t, err)
+
+	assert.True(t, c.IsHoliday(time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)))   // Easter Monday
+	assert.True(t, c.IsHoliday(time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC))) // substitute Boxing Day
+	assert.False(t, c.IsHoliday(time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, c.IsHoliday(time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCovers(t *testing.T) {
+	c, err := Named("england-and-wales")
+	require.NoError(t, err)
+	assert.True(t, c.Covers(time.Date(englandAndWalesUntil, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, c.Covers(time.Date(englandAndWalesUntil+1, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	none, err := Named("none")
+	require.NoError(t, err)
+	assert.True(t, none.Covers(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	var nilCal *Calendar
+	assert.True(t, nilCal.Covers(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestNamed_CopiesBundledDates(t *testing.T) {
+	a, err := Named("england-and-wales")
+	require.NoError(t, err)
+	extra := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	a.Add(extra)
+
+	b, err := Named("england-and-wales")
+	require.NoError(t, err)
+	assert.False(t, b.IsHoliday(extra))
+}
+
+func TestNamed_None(t *testing.T) {
+	c, err := Named("none")
+	require.NoError(t, err)
+
+	assert.False(t, c.IsHoliday(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestNamed_Unknown(t *testing.T) {
+	_, err := Named("scotland")
+	assert.EqualError(t, err, `unknown holiday calendar: "scotland"`)
+}
+
+func TestIsHoliday_UsesLocalDate(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	c := New(time.Date(2026, 5, 25, 0, 0, 0, 0, time.UTC))
+
+	assert.True(t, c.IsHoliday(time.Date(2026, 5, 25, 0, 0, 0, 0, london)))
+	assert.False(t, c.IsHoliday(time.Date(2026, 5, 26, 0, 0, 0, 0, london)))
+}
+
+func TestIsHoliday_NilCalendar(t *testing.T) {
+	var c *Calendar
+	assert.False(t, c.IsHoliday(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)))
+}
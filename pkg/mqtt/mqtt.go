@@ -0,0 +1,219 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/dateutil"
+	"github.com/stebennett/bin-notifier/pkg/scraper"
+)
+
+// Client is the subset of an MQTT client used by Publisher.
+// This allows for dependency injection and mocking in tests.
+type Client interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+	Disconnect()
+}
+
+// Publisher publishes bin collection state as Home Assistant MQTT discovery entities.
+// Each location becomes a device with a date sensor per bin type and a
+// "bins tomorrow" binary sensor.
+type Publisher struct {
+	client          Client
+	discoveryPrefix string
+	baseTopic       string
+}
+
+type device struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+type discoveryConfig struct {
+	Name                string `json:"name"`
+	UniqueID            string `json:"unique_id"`
+	ObjectID            string `json:"object_id"`
+	StateTopic          string `json:"state_topic"`
+	JSONAttributesTopic string `json:"json_attributes_topic"`
+	DeviceClass         string `json:"device_class,omitempty"`
+	Icon                string `json:"icon,omitempty"`
+	Device              device `json:"device"`
+}
+
+type sensorAttributes struct {
+	Location  string `json:"location"`
+	BinType   string `json:"bin_type"`
+	DaysUntil int    `json:"days_until"`
+}
+
+type tomorrowAttributes struct {
+	Location string   `json:"location"`
+	Types    []string `json:"types"`
+}
+
+var slugExp = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slug(s string) string {
+	return strings.Trim(slugExp.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
+
+// NewPublisher connects to the configured broker and returns a Publisher.
+func NewPublisher(cfg config.MQTTConfig) (*Publisher, error) {
+	client, err := connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewPublisherWithClient(client, cfg.DiscoveryPrefix, cfg.BaseTopic), nil
+}
+
+// NewPublisherWithClient creates a Publisher with a custom Client.
+// This is useful for testing with a mock implementation.
+func NewPublisherWithClient(client Client, discoveryPrefix string, baseTopic string) *Publisher {
+	return &Publisher{
+		client:          client,
+		discoveryPrefix: discoveryPrefix,
+		baseTopic:       baseTopic,
+	}
+}
+
+// PublishLocation publishes discovery config and state for a location's next
+// collection per bin type, relative to today.
+func (p *Publisher) PublishLocation(loc config.Location, binTimes []scraper.BinTime, today time.Time) error {
+	locSlug := slug(loc.Label)
+	dev := device{
+		Identifiers:  []string{"bin_notifier_" + locSlug},
+		Name:         loc.Label + " Bins",
+		Manufacturer: "bin-notifier",
+	}
+
+	var tomorrowTypes []string
+	for _, bt := range binTimes {
+		typeSlug := slug(bt.Type)
+		objectID := "bin_notifier_" + locSlug + "_" + typeSlug
+		topic := p.baseTopic + "/" + locSlug + "/" + typeSlug
+
+		err := p.publishJSON(p.discoveryPrefix+"/sensor/"+objectID+"/config", discoveryConfig{
+			Name:                bt.Type,
+			UniqueID:            objectID,
+			ObjectID:            objectID,
+			StateTopic:          topic + "/state",
+			JSONAttributesTopic: topic + "/attributes",
+			DeviceClass:         "date",
+			Icon:                "mdi:trash-can",
+			Device:              dev,
+		})
+		if err != nil {
+			return err
+		}
+
+		daysUntil := dateutil.DaysBetween(today, bt.CollectionTime)
+		if daysUntil == 1 {
+			tomorrowTypes = append(tomorrowTypes, bt.Type)
+		}
+		if err := p.publish(topic+"/state", []byte(bt.CollectionTime.Format("2006-01-02"))); err != nil {
+			return err
+		}
+		err = p.publishJSON(topic+"/attributes", sensorAttributes{
+			Location:  loc.Label,
+			BinType:   bt.Type,
+			DaysUntil: daysUntil,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	objectID := "bin_notifier_" + locSlug + "_bins_tomorrow"
+	topic := p.baseTopic + "/" + locSlug + "/bins_tomorrow"
+	err := p.publishJSON(p.discoveryPrefix+"/binary_sensor/"+objectID+"/config", discoveryConfig{
+		Name:                "Bins tomorrow",
+		UniqueID:            objectID,
+		ObjectID:            objectID,
+		StateTopic:          topic + "/state",
+		JSONAttributesTopic: topic + "/attributes",
+		Icon:                "mdi:delete-alert",
+		Device:              dev,
+	})
+	if err != nil {
+		return err
+	}
+
+	state := "OFF"
+	if len(tomorrowTypes) > 0 {
+		state = "ON"
+	}
+	sort.Strings(tomorrowTypes)
+	if err := p.publish(topic+"/state", []byte(state)); err != nil {
+		return err
+	}
+	return p.publishJSON(topic+"/attributes", tomorrowAttributes{
+		Location: loc.Label,
+		Types:    tomorrowTypes,
+	})
+}
+
+// Close disconnects from the broker.
+func (p *Publisher) Close() {
+	p.client.Disconnect()
+}
+
+func (p *Publisher) publishJSON(topic string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return p.publish(topic, data)
+}
+
+// publish sends a retained QoS 1 message so Home Assistant picks up the latest
+// state after a restart.
+func (p *Publisher) publish(topic string, payload []byte) error {
+	if err := p.client.Publish(topic, 1, true, payload); err != nil {
+		return fmt.Errorf("publish %s: %w", topic, err)
+	}
+	return nil
+}
+
+// pahoClient adapts the Paho MQTT client to the Client interface.
+type pahoClient struct {
+	client paho.Client
+}
+
+func connect(cfg config.MQTTConfig) (*pahoClient, error) {
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectTimeout(10 * time.Second)
+
+	client := paho.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(15 * time.Second) {
+		return nil, fmt.Errorf("timed out connecting to %s", cfg.Broker)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", cfg.Broker, err)
+	}
+	return &pahoClient{client: client}, nil
+}
+
+func (c *pahoClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	token := c.client.Publish(topic, qos, retained, payload)
+	if !token.WaitTimeout(10 * time.Second) {
+		return fmt.Errorf("timed out")
+	}
+	return token.Error()
+}
+
+func (c *pahoClient) Disconnect() {
+	c.client.Disconnect(250)
+}
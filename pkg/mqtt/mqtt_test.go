@@ -0,0 +1,246 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type published struct {
+	qos      byte
+	retained bool
+	payload  string
+}
+
+// mockClient is a mock implementation of Client for testing
+type mockClient struct {
+	messages     map[string]published
+	order        []string
+	err          error
+	disconnected bool
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{messages: make(map[string]published)}
+}
+
+func (m *mockClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.messages[topic] = published{qos: qos, retained: retained, payload: string(payload)}
+	m.order = append(m.order, topic)
+	return nil
+}
+
+func (m *mockClient) Disconnect() {
+	m.disconnected = true
+}
+
+func testLocation() config.Location {
+	return config.Location{Label: "Home", PostCode: "RG12 1AB"}
+}
+
+func testBinTimes() []scraper.BinTime {
+	return []scraper.BinTime{
+		{Type: "General Waste", CollectionTime: time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{Type: "Garden Waste", CollectionTime: time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestPublishLocation_PublishesSensorPerBinType(t *testing.T) {
+	client := newMockClient()
+	p := NewPublisherWithClient(client, "homeassistant", "bin-notifier")
+	today := time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC)
+
+	err := p.PublishLocation(testLocation(), testBinTimes(), today)
+	require.NoError(t, err)
+
+	cfgMsg, ok := client.messages["homeassistant/sensor/bin_notifier_home_general_waste/config"]
+	require.True(t, ok)
+	assert.True(t, cfgMsg.retained)
+	assert.Equal(t, byte(1), cfgMsg.qos)
+	var discovery map[string]any
+	require.NoError(t, json.Unmarshal([]byte(cfgMsg.payload), &discovery))
+	assert.Equal(t, "General Waste", discovery["name"])
+	assert.Equal(t, "date", discovery["device_class"])
+	assert.Equal(t, "bin-notifier/home/general_waste/state", discovery["state_topic"])
+	assert.Equal(t, "bin-notifier/home/general_waste/attributes", discovery["json_attributes_topic"])
+	assert.Equal(t, "Home Bins", discovery["device"].(map[string]any)["name"])
+
+	assert.Equal(t, "2026-01-16", client.messages["bin-notifier/home/general_waste/state"].payload)
+	assert.JSONEq(t, `{"location":"Home","bin_type":"General Waste","days_until":1}`,
+		client.messages["bin-notifier/home/general_waste/attributes"].payload)
+	assert.Equal(t, "2026-01-23", client.messages["bin-notifier/home/garden_waste/state"].payload)
+	assert.JSONEq(t, `{"location":"Home","bin_type":"Garden Waste","days_until":8}`,
+		client.messages["bin-notifier/home/garden_waste/attributes"].payload)
+}
+
+func TestPublishLocation_BinsTomorrowBinarySensor(t *testing.T) {
+	client := newMockClient()
+	p := NewPublisherWithClient(client, "homeassistant", "bin-notifier")
+
+	err := p.PublishLocation(testLocation(), testBinTimes(), time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	_, ok := client.messages["homeassistant/binary_sensor/bin_notifier_home_bins_tomorrow/config"]
+	assert.True(t, ok)
+	assert.Equal(t, "ON", client.messages["bin-notifier/home/bins_tomorrow/state"].payload)
+	assert.JSONEq(t, `{"location":"Home","types":["General Waste"]}`,
+		client.messages["bin-notifier/home/bins_tomorrow/attributes"].payload)
+
+	err = p.PublishLocation(testLocation(), testBinTimes(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "OFF", client.messages["bin-notifier/home/bins_tomorrow/state"].payload)
+}
+
+func TestPublishLocation_ReturnsPublishError(t *testing.T) {
+	client := newMockClient()
+	client.err = errors.New("not connected")
+	p := NewPublisherWithClient(client, "homeassistant", "bin-notifier")
+
+	err := p.PublishLocation(testLocation(), testBinTimes(), time.Now())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not connected")
+}
+
+func TestClose_Disconnects(t *testing.T) {
+	client := newMockClient()
+	p := NewPublisherWithClient(client, "homeassistant", "bin-notifier")
+
+	p.Close()
+
+	assert.True(t, client.disconnected)
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "home", slug("Home"))
+	assert.Equal(t, "mum_s_house", slug("Mum's House"))
+	assert.Equal(t, "food_waste", slug(" Food  Waste "))
+}
+
+// fakeBroker is a minimal local MQTT 3.1.1 broker that acknowledges connections
+// and QoS 1 publishes and records retained messages.
+type fakeBroker struct {
+	listener net.Listener
+	mu       sync.Mutex
+	retained map[string]string
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &fakeBroker{listener: l, retained: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return b
+}
+
+func (b *fakeBroker) addr() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *fakeBroker) get(topic string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v, ok := b.retained[topic]
+	return v, ok
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		header, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+
+		switch header >> 4 {
+		case 1: // CONNECT
+			conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+		case 3: // PUBLISH
+			qos := (header >> 1) & 0x03
+			topicLen := int(binary.BigEndian.Uint16(body))
+			topic := string(body[2 : 2+topicLen])
+			rest := body[2+topicLen:]
+			if qos > 0 {
+				conn.Write([]byte{0x40, 0x02, rest[0], rest[1]})
+				rest = rest[2:]
+			}
+			if header&0x01 == 1 {
+				b.mu.Lock()
+				b.retained[topic] = string(rest)
+				b.mu.Unlock()
+			}
+		case 12: // PINGREQ
+			conn.Write([]byte{0xD0, 0x00})
+		case 14: // DISCONNECT
+			return
+		}
+	}
+}
+
+func TestNewPublisher_PublishesToLocalBroker(t *testing.T) {
+	broker := newFakeBroker(t)
+	p, err := NewPublisher(config.MQTTConfig{
+		Broker:          broker.addr(),
+		ClientID:        "bin-notifier-test",
+		DiscoveryPrefix: "homeassistant",
+		BaseTopic:       "bins",
+	})
+	require.NoError(t, err)
+	defer p.Close()
+
+	err = p.PublishLocation(testLocation(), testBinTimes(), time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	state, ok := broker.get("bins/home/general_waste/state")
+	assert.True(t, ok)
+	assert.Equal(t, "2026-01-16", state)
+	state, ok = broker.get("bins/home/bins_tomorrow/state")
+	assert.True(t, ok)
+	assert.Equal(t, "ON", state)
+	_, ok = broker.get("homeassistant/sensor/bin_notifier_home_garden_waste/config")
+	assert.True(t, ok)
+}
+
+func TestNewPublisher_ConnectionError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := "tcp://" + l.Addr().String()
+	l.Close()
+
+	_, err = NewPublisher(config.MQTTConfig{Broker: addr, ClientID: "test"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Policy controls how many times an operation is attempted and how long to wait
+// between attempts. Delays double after each failure, starting at InitialDelay
+// and capped at MaxDelay.
+type Policy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// Delay returns the wait after the given failed attempt (starting at 1). Half of
+// the backoff is fixed and half is random so concurrent retries spread out.
+func (p Policy) Delay(attempt int, random func() float64) time.Duration {
+	backoff := p.InitialDelay
+	for i := 1; i < attempt && backoff < p.MaxDelay; i++ {
+		backoff *= 2
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	half := backoff / 2
+	return half + time.Duration(random()*float64(backoff-half))
+}
+
+// Do calls fn until it succeeds, fails with an error that shouldRetry rejects, or
+// MaxAttempts is reached, sleeping between attempts. fn receives the attempt
+// number starting at 1. Do returns the number of attempts made and the last error.
+func Do(p Policy, sleep func(time.Duration), shouldRetry func(error) bool, fn func(attempt int) error) (int, error) {
+	attempt := 1
+	for {
+		err := fn(attempt)
+		if err == nil || attempt >= p.MaxAttempts || !shouldRetry(err) {
+			return attempt, err
+		}
+		sleep(p.Delay(attempt, rand.Float64))
+		attempt++
+	}
+}
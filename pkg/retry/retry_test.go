@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPolicy() Policy {
+	return Policy{MaxAttempts: 4, InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+}
+
+func TestDelay_ExponentialWithCap(t *testing.T) {
+	p := testPolicy()
+	noJitter := func() float64 { return 1 }
+
+	assert.Equal(t, 1*time.Second, p.Delay(1, noJitter))
+	assert.Equal(t, 2*time.Second, p.Delay(2, noJitter))
+	assert.Equal(t, 4*time.Second, p.Delay(3, noJitter))
+	assert.Equal(t, 5*time.Second, p.Delay(4, noJitter))
+	assert.Equal(t, 5*time.Second, p.Delay(50, noJitter))
+}
+
+func TestDelay_JitterKeepsAtLeastHalf(t *testing.T) {
+	p := testPolicy()
+
+	assert.Equal(t, 1*time.Second, p.Delay(2, func() float64 { return 0 }))
+	assert.Equal(t, 1500*time.Millisecond, p.Delay(2, func() float64 { return 0.5 }))
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	var slept []time.Duration
+	calls := 0
+
+	attempts, err := Do(testPolicy(), func(d time.Duration) { slept = append(slept, d) },
+		func(error) bool { return true },
+		func(attempt int) error {
+			calls++
+			assert.Equal(t, calls, attempt)
+			if attempt < 3 {
+				return errors.New("503")
+			}
+			return nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, slept, 2)
+	assert.GreaterOrEqual(t, slept[0], 500*time.Millisecond)
+	assert.LessOrEqual(t, slept[0], 1*time.Second)
+	assert.GreaterOrEqual(t, slept[1], 1*time.Second)
+	assert.LessOrEqual(t, slept[1], 2*time.Second)
+}
+
+func TestDo_StopsAtMaxAttempts(t *testing.T) {
+	attempts, err := Do(testPolicy(), func(time.Duration) {}, func(error) bool { return true },
+		func(int) error { return errors.New("503") })
+
+	assert.EqualError(t, err, "503")
+	assert.Equal(t, 4, attempts)
+}
+
+func TestDo_StopsOnPermanentError(t *testing.T) {
+	slept := false
+	attempts, err := Do(testPolicy(), func(time.Duration) { slept = true }, func(error) bool { return false },
+		func(int) error { return errors.New("invalid number") })
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.False(t, slept)
+}
+
+func TestDo_SingleAttemptWhenUnset(t *testing.T) {
+	attempts, err := Do(Policy{}, func(time.Duration) { t.Fatal("unexpected sleep") }, func(error) bool { return true },
+		func(int) error { return errors.New("503") })
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,305 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules needed to
+// describe bin collection schedules, e.g. "FREQ=MONTHLY;BYDAY=1MO" for the first
+// Monday of each month.
+package rrule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/dateutil"
+)
+
+// Frequency is a rule's FREQ.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry: a weekday, optionally limited to the Nth (or,
+// when negative, Nth from last) occurrence in the month or year.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed recurrence rule. Only calendar dates are considered; times
+// of day are ignored.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonth    []time.Month
+	ByMonthDay []int
+	// Until is the last date the rule can occur on, or zero for no end.
+	Until time.Time
+	// Start is the rule's first possible date (DTSTART), or zero for none.
+	// INTERVAL counts periods from Start.
+	Start time.Time
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE;BYMONTH=3,4,5".
+// An "RRULE:" prefix is allowed. start is the rule's DTSTART and may be zero,
+// but is needed for INTERVAL above 1 and when no BY parts pin down the dates.
+// FREQ, INTERVAL, BYDAY, BYMONTH, BYMONTHDAY and UNTIL are supported.
+func Parse(s string, start time.Time) (*Rule, error) {
+	r := &Rule{Interval: 1, Start: start}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch f := Frequency(strings.ToUpper(value)); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err != nil || r.Interval < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive number, got %q", value)
+			}
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTH":
+			r.ByMonth, err = parseByMonth(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		case "UNTIL":
+			r.Until, err = time.Parse("20060102", value[:min(len(value), 8)])
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", value)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// validate checks the parts fit together and fills in the BY parts implied by
+// Start, as RFC 5545 does from DTSTART.
+func (r *Rule) validate() error {
+	hasStart := !r.Start.IsZero()
+	if r.Freq == "" {
+		return fmt.Errorf("FREQ is required")
+	}
+	if r.Interval > 1 && !hasStart {
+		return fmt.Errorf("INTERVAL above 1 needs a start date")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N == 0 {
+			continue
+		}
+		switch {
+		case r.Freq == Monthly || (r.Freq == Yearly && len(r.ByMonth) > 0):
+			if wd.N < -5 || wd.N > 5 {
+				return fmt.Errorf("BYDAY position must be between -5 and 5 in a month")
+			}
+		case r.Freq == Yearly:
+			if wd.N < -53 || wd.N > 53 {
+				return fmt.Errorf("BYDAY position must be between -53 and 53 in a year")
+			}
+		default:
+			return fmt.Errorf("BYDAY positions are only allowed with FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+
+	switch r.Freq {
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			if !hasStart {
+				return fmt.Errorf("FREQ=WEEKLY needs BYDAY or a start date")
+			}
+			r.ByDay = []WeekdayNum{{Weekday: r.Start.Weekday()}}
+		}
+	case Monthly:
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			if !hasStart {
+				return fmt.Errorf("FREQ=MONTHLY needs BYDAY, BYMONTHDAY or a start date")
+			}
+			r.ByMonthDay = []int{r.Start.Day()}
+		}
+	case Yearly:
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			if !hasStart {
+				return fmt.Errorf("FREQ=YEARLY needs BYDAY, BYMONTHDAY or a start date")
+			}
+			r.ByMonthDay = []int{r.Start.Day()}
+			if len(r.ByMonth) == 0 {
+				r.ByMonth = []time.Month{r.Start.Month()}
+			}
+		}
+	}
+	return nil
+}
+
+// Occurs reports whether the rule falls on date's calendar day.
+func (r *Rule) Occurs(date time.Time) bool {
+	if !r.Start.IsZero() && dateutil.DaysBetween(r.Start, date) < 0 {
+		return false
+	}
+	if !r.Until.IsZero() && dateutil.DaysBetween(r.Until, date) > 0 {
+		return false
+	}
+	if len(r.ByMonth) > 0 && !contains(r.ByMonth, date.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(date) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !r.matchesDay(date) {
+		return false
+	}
+	return r.onInterval(date)
+}
+
+func (r *Rule) matchesMonthDay(date time.Time) bool {
+	last := daysIn(date.Year(), date.Month())
+	for _, n := range r.ByMonthDay {
+		if n == date.Day() || (n < 0 && last+n+1 == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesDay(date time.Time) bool {
+	// Positions count within the month, or within the year for a yearly rule
+	// with no BYMONTH.
+	day, length := date.Day(), daysIn(date.Year(), date.Month())
+	if r.Freq == Yearly && len(r.ByMonth) == 0 {
+		day, length = date.YearDay(), time.Date(date.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday != date.Weekday() {
+			continue
+		}
+		switch {
+		case wd.N == 0,
+			wd.N > 0 && (day-1)/7+1 == wd.N,
+			wd.N < 0 && (length-day)/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+// onInterval reports whether date falls in a period counted in whole
+// INTERVALs from Start. Weeks start on Monday.
+func (r *Rule) onInterval(date time.Time) bool {
+	if r.Interval == 1 {
+		return true
+	}
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = dateutil.DaysBetween(r.Start, date)
+	case Weekly:
+		periods = dateutil.DaysBetween(mondayOf(r.Start), mondayOf(date)) / 7
+	case Monthly:
+		periods = (date.Year()-r.Start.Year())*12 + int(date.Month()-r.Start.Month())
+	case Yearly:
+		periods = date.Year() - r.Start.Year()
+	}
+	return periods%r.Interval == 0
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, v := range strings.Split(strings.ToUpper(value), ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", v)
+		}
+		day, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", v)
+		}
+		wd := WeekdayNum{Weekday: day}
+		if pos := v[:len(v)-2]; pos != "" {
+			n, err := strconv.Atoi(pos)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid BYDAY %q", v)
+			}
+			wd.N = n
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+func parseByMonth(value string) ([]time.Month, error) {
+	var months []time.Month
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 12 {
+			return nil, fmt.Errorf("invalid BYMONTH %q", v)
+		}
+		months = append(months, time.Month(n))
+	}
+	return months, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q", v)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func mondayOf(t time.Time) time.Time {
+	return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+}
+
+func contains(months []time.Month, m time.Month) bool {
+	for _, v := range months {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
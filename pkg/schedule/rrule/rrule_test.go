@@ -0,0 +1,129 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// occurrences returns the dates in [from, to] that the rule falls on.
+func occurrences(r *Rule, from, to time.Time) []string {
+	var dates []string
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if r.Occurs(d) {
+			dates = append(dates, d.Format("2006-01-02"))
+		}
+	}
+	return dates
+}
+
+func TestParse_FirstMondayOfMonth(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;BYDAY=1MO", time.Time{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-03-02", "2026-04-06", "2026-05-04"},
+		occurrences(r, date(2026, 3, 1), date(2026, 5, 31)))
+}
+
+func TestParse_LastFridayOfMonth(t *testing.T) {
+	r, err := Parse("RRULE:FREQ=MONTHLY;BYDAY=-1FR", time.Time{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-01-30", "2026-02-27"},
+		occurrences(r, date(2026, 1, 1), date(2026, 2, 28)))
+}
+
+func TestParse_FortnightlyInSeason(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=WE;BYMONTH=3,4,5,6,7,8,9,10,11", date(2026, 3, 4))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-11-11", "2026-11-25"},
+		occurrences(r, date(2026, 11, 1), date(2027, 3, 1)))
+	assert.True(t, r.Occurs(date(2027, 3, 3)), "season restarts on the fortnightly cycle")
+	assert.False(t, r.Occurs(date(2026, 2, 25)), "before start")
+}
+
+func TestParse_WeeklyDefaultsToStartWeekday(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;INTERVAL=3", date(2026, 3, 5))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-03-05", "2026-03-26", "2026-04-16"},
+		occurrences(r, date(2026, 3, 1), date(2026, 4, 30)))
+}
+
+func TestParse_MonthlyByMonthDayAndUntil(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20260301T000000Z", time.Time{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-01-31", "2026-02-01", "2026-02-28", "2026-03-01"},
+		occurrences(r, date(2026, 1, 31), date(2026, 4, 30)))
+}
+
+func TestParse_EveryOtherMonth(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;INTERVAL=2", date(2026, 1, 15))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-01-15", "2026-03-15", "2026-05-15"},
+		occurrences(r, date(2026, 1, 1), date(2026, 6, 30)))
+}
+
+func TestParse_Yearly(t *testing.T) {
+	r, err := Parse("FREQ=YEARLY;BYMONTH=1;BYDAY=2SA", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-01-10"}, occurrences(r, date(2026, 1, 1), date(2026, 12, 31)))
+
+	r, err = Parse("FREQ=YEARLY", date(2025, 12, 27))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-12-27"}, occurrences(r, date(2026, 1, 1), date(2026, 12, 31)))
+}
+
+func TestOccurs_IgnoresTimeZone(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	r, err := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", date(2026, 3, 2))
+	require.NoError(t, err)
+
+	// 2026-03-30 is after the clocks go forward.
+	assert.True(t, r.Occurs(time.Date(2026, 3, 30, 0, 0, 0, 0, london)))
+	assert.False(t, r.Occurs(time.Date(2026, 3, 23, 0, 0, 0, 0, london)))
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		start  time.Time
+		errMsg string
+	}{
+		{"empty", "", time.Time{}, "empty rule"},
+		{"no freq", "BYDAY=MO", time.Time{}, "FREQ is required"},
+		{"bad freq", "FREQ=HOURLY;BYDAY=MO", time.Time{}, `unsupported FREQ "HOURLY"`},
+		{"bad part", "FREQ=WEEKLY;BYDAY", time.Time{}, `invalid rule part "BYDAY"`},
+		{"unsupported part", "FREQ=WEEKLY;COUNT=10", time.Time{}, `unsupported rule part "COUNT"`},
+		{"bad interval", "FREQ=WEEKLY;INTERVAL=0;BYDAY=MO", time.Time{}, `INTERVAL must be a positive number, got "0"`},
+		{"interval without start", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", time.Time{}, "INTERVAL above 1 needs a start date"},
+		{"bad day", "FREQ=WEEKLY;BYDAY=XX", time.Time{}, `invalid BYDAY "XX"`},
+		{"weekly position", "FREQ=WEEKLY;BYDAY=1MO", time.Time{}, "BYDAY positions are only allowed with FREQ=MONTHLY or FREQ=YEARLY"},
+		{"position out of range", "FREQ=MONTHLY;BYDAY=6MO", time.Time{}, "BYDAY position must be between -5 and 5 in a month"},
+		{"bad month", "FREQ=WEEKLY;BYDAY=MO;BYMONTH=13", time.Time{}, `invalid BYMONTH "13"`},
+		{"bad month day", "FREQ=MONTHLY;BYMONTHDAY=32", time.Time{}, `invalid BYMONTHDAY "32"`},
+		{"weekly month day", "FREQ=WEEKLY;BYMONTHDAY=1", time.Time{}, "BYMONTHDAY is not allowed with FREQ=WEEKLY"},
+		{"bad until", "FREQ=WEEKLY;BYDAY=MO;UNTIL=2026", time.Time{}, `invalid UNTIL "2026"`},
+		{"weekly without day", "FREQ=WEEKLY", time.Time{}, "FREQ=WEEKLY needs BYDAY or a start date"},
+		{"monthly without day", "FREQ=MONTHLY;BYMONTH=3", time.Time{}, "FREQ=MONTHLY needs BYDAY, BYMONTHDAY or a start date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.rule, tt.start)
+			require.Error(t, err)
+			assert.Equal(t, tt.errMsg, err.Error())
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package schedule
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/stebennett/bin-notifier/pkg/config"
 	"github.com/stebennett/bin-notifier/pkg/dateutil"
+	"github.com/stebennett/bin-notifier/pkg/holidays"
+	"github.com/stebennett/bin-notifier/pkg/schedule/rrule"
 )
 
 // Collection represents a projected bin collection for a specific date and location.
@@ -16,26 +20,23 @@ type Collection struct {
 }
 
 // ProjectCollections returns all projected collections for the given locations
-// within the date range [from, to] inclusive, sorted by date.
-func ProjectCollections(locations []config.Location, from, to time.Time) []Collection {
+// within the date range [from, to] inclusive, sorted by date. Dates are the days
+// that from and to fall on in zone, and collections are dated midnight in zone.
+// Collections are moved by each location's holiday shift rule using cal, then
+// adjusted by its exceptions.
+func ProjectCollections(locations []config.Location, from, to time.Time, zone *time.Location, cal *holidays.Calendar) []Collection {
 	var results []Collection
 
+	from = dateutil.Today(from, zone)
+	to = dateutil.Today(to, zone)
 	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
 		for _, loc := range locations {
+			days, err := CollectionsOn(loc, d, cal)
+			if err != nil {
+				continue
+			}
 			var types []string
-			for _, cd := range loc.CollectionDays {
-				if d.Weekday() != cd.Day {
-					continue
-				}
-				if cd.EveryNWeeks > 1 {
-					refDate, err := time.Parse("2006-01-02", cd.ReferenceDate)
-					if err != nil {
-						continue
-					}
-					if !dateutil.IsOnWeek(refDate, d, cd.EveryNWeeks) {
-						continue
-					}
-				}
+			for _, cd := range days {
 				types = append(types, cd.Types...)
 			}
 			if len(types) > 0 {
@@ -57,3 +58,197 @@ func ProjectCollections(locations []config.Location, from, to time.Time) []Colle
 
 	return results
 }
+
+// CollectionsOn returns the location's configured collections expected on date.
+// A collection moved by the location's holiday shift rule is expected on the day
+// it moves to rather than its usual day, and the location's exceptions are then
+// applied: skipped and moved-away collections are removed, and collections moved
+// to date and one-off extras are added.
+func CollectionsOn(loc config.Location, date time.Time, cal *holidays.Calendar) ([]config.CollectionDay, error) {
+	days, err := regularCollectionsOn(loc, date, cal)
+	if err != nil {
+		return nil, err
+	}
+
+	day := date.Format("2006-01-02")
+	for _, e := range loc.Exceptions {
+		switch {
+		case e.Skip == day, e.From == day:
+			days = removeTypes(days, e.Types)
+		case e.To == day:
+			from, err := time.ParseInLocation("2006-01-02", e.From, date.Location())
+			if err != nil {
+				return nil, fmt.Errorf("invalid exception date: %w", err)
+			}
+			moved, err := regularCollectionsOn(loc, from, cal)
+			if err != nil {
+				return nil, err
+			}
+			days = append(days, keepTypes(moved, e.Types)...)
+		case e.Extra == day:
+			days = append(days, config.CollectionDay{Day: date.Weekday(), Types: e.Types, EveryNWeeks: 1})
+		}
+	}
+	return days, nil
+}
+
+// regularCollectionsOn returns the collections expected on date from the
+// location's schedule and holiday shift rule, before exceptions.
+func regularCollectionsOn(loc config.Location, date time.Time, cal *holidays.Calendar) ([]config.CollectionDay, error) {
+	var days []config.CollectionDay
+	for back := 0; back <= loc.HolidayShift.Days; back++ {
+		usual := date.AddDate(0, 0, -back)
+		if shiftFor(loc.HolidayShift, usual, cal) != back {
+			continue
+		}
+		for _, cd := range loc.CollectionDays {
+			ok, err := scheduledOn(cd, usual)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				days = append(days, cd)
+			}
+		}
+	}
+	return days, nil
+}
+
+// removeTypes drops the given bin types from days, or every collection when types
+// is empty. Collections left with no types are dropped.
+func removeTypes(days []config.CollectionDay, types []string) []config.CollectionDay {
+	if len(types) == 0 {
+		return nil
+	}
+	return filterTypes(days, func(t string) bool { return !containsFold(types, t) })
+}
+
+// keepTypes keeps only the given bin types in days, or every collection when
+// types is empty.
+func keepTypes(days []config.CollectionDay, types []string) []config.CollectionDay {
+	if len(types) == 0 {
+		return days
+	}
+	return filterTypes(days, func(t string) bool { return containsFold(types, t) })
+}
+
+func filterTypes(days []config.CollectionDay, keep func(string) bool) []config.CollectionDay {
+	var result []config.CollectionDay
+	for _, cd := range days {
+		var types []string
+		for _, t := range cd.Types {
+			if keep(t) {
+				types = append(types, t)
+			}
+		}
+		if len(types) > 0 {
+			cd.Types = types
+			result = append(result, cd)
+		}
+	}
+	return result
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduledOn reports whether a collection day falls on date before any holiday shift.
+func scheduledOn(cd config.CollectionDay, date time.Time) (bool, error) {
+	if !activeOn(cd, date) {
+		return false, nil
+	}
+	if cd.RRule != "" {
+		rule, err := ruleFor(cd)
+		if err != nil {
+			return false, err
+		}
+		return rule.Occurs(date), nil
+	}
+	if date.Weekday() != cd.Day {
+		return false, nil
+	}
+	if cd.EveryNWeeks > 1 {
+		refDate, err := time.Parse("2006-01-02", cd.ReferenceDate)
+		if err != nil {
+			return false, fmt.Errorf("invalid reference_date in collection schedule: %w", err)
+		}
+		return dateutil.IsOnWeek(refDate, date, cd.EveryNWeeks), nil
+	}
+	return true, nil
+}
+
+// activeOn reports whether date falls within a collection day's active window.
+// Month-day bounds are compared with date's month and day, and date bounds with
+// the full date.
+func activeOn(cd config.CollectionDay, date time.Time) bool {
+	day := date.Format("2006-01-02")
+	monthDay := date.Format("01-02")
+	value := func(bound string) string {
+		if len(bound) == len("01-02") {
+			return monthDay
+		}
+		return day
+	}
+
+	from, until := cd.ActiveFrom, cd.ActiveUntil
+	if len(from) == len("01-02") && len(until) == len("01-02") && from > until {
+		return monthDay >= from || monthDay <= until
+	}
+	if from != "" && value(from) < from {
+		return false
+	}
+	if until != "" && value(until) > until {
+		return false
+	}
+	return true
+}
+
+// ruleFor returns a collection day's recurrence rule, parsing it when the
+// config was not loaded through config.LoadConfig.
+func ruleFor(cd config.CollectionDay) (*rrule.Rule, error) {
+	if cd.Rule != nil {
+		return cd.Rule, nil
+	}
+	var start time.Time
+	if cd.ReferenceDate != "" {
+		var err error
+		start, err = time.Parse("2006-01-02", cd.ReferenceDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference_date in collection schedule: %w", err)
+		}
+	}
+	rule, err := rrule.Parse(cd.RRule, start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule in collection schedule: %w", err)
+	}
+	return rule, nil
+}
+
+// shiftFor returns how many days a collection usually on date moves because of
+// a holiday in cal.
+func shiftFor(shift config.HolidayShift, date time.Time, cal *holidays.Calendar) int {
+	if shift.Days == 0 {
+		return 0
+	}
+	if shift.Scope == config.HolidayScopeDay {
+		if cal.IsHoliday(date) {
+			return shift.Days
+		}
+		return 0
+	}
+	// Look back from date to the Monday of its week.
+	for d := date; ; d = d.AddDate(0, 0, -1) {
+		if cal.IsHoliday(d) {
+			return shift.Days
+		}
+		if d.Weekday() == time.Monday {
+			return 0
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stebennett/bin-notifier/pkg/config"
+	"github.com/stebennett/bin-notifier/pkg/holidays"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,7 +22,7 @@ func TestProjectCollections_WeeklySchedule(t *testing.T) {
 	from := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC) // Monday
 	to := time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)   // Sunday
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 
 	assert.Len(t, results, 1)
 	assert.Equal(t, time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC), results[0].Date)
@@ -49,7 +50,7 @@ func TestProjectCollections_FortnightlySchedule(t *testing.T) {
 	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)  // Monday
 	to := time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)    // Sunday
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 
 	assert.Len(t, results, 1)
 	assert.Equal(t, time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC), results[0].Date)
@@ -75,7 +76,7 @@ func TestProjectCollections_MultipleLocations(t *testing.T) {
 	from := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC) // Tuesday
 	to := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)   // Tuesday
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 
 	assert.Len(t, results, 2)
 	assert.Equal(t, "Home", results[0].Location)
@@ -96,7 +97,7 @@ func TestProjectCollections_EmptyRange(t *testing.T) {
 	from := time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 	assert.Empty(t, results)
 }
 
@@ -114,7 +115,7 @@ func TestProjectCollections_NoMatchingDays(t *testing.T) {
 	from := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 	assert.Empty(t, results)
 }
 
@@ -132,7 +133,7 @@ func TestProjectCollections_MultipleCollectionDaysSameDay(t *testing.T) {
 	from := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC) // Tuesday
 	to := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 
 	assert.Len(t, results, 1)
 	assert.Equal(t, []string{"Recycling", "Food Waste"}, results[0].Types)
@@ -152,8 +153,276 @@ func TestProjectCollections_SortedByDate(t *testing.T) {
 	from := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC) // Monday
 	to := time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)   // Sunday
 
-	results := ProjectCollections(locations, from, to)
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
 
 	assert.Len(t, results, 2)
 	assert.True(t, results[0].Date.Before(results[1].Date))
 }
+
+func TestProjectCollections_LocalTimezoneAcrossDST(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations := []config.Location{
+		{
+			Label: "Home",
+			CollectionDays: []config.CollectionDay{
+				{Day: time.Monday, Types: []string{"Recycling"}, EveryNWeeks: 2, ReferenceDate: "2026-03-16"},
+			},
+		},
+	}
+
+	// 00:30 BST on Monday 30 March is still Sunday in UTC.
+	from := time.Date(2026, 3, 29, 23, 30, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 14)
+
+	results := ProjectCollections(locations, from, to, london, nil)
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, time.Date(2026, 3, 30, 0, 0, 0, 0, london), results[0].Date)
+	assert.Equal(t, time.Date(2026, 4, 13, 0, 0, 0, 0, london), results[1].Date)
+}
+
+func easter2026() *holidays.Calendar {
+	return holidays.New(
+		time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), // Good Friday
+		time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC), // Easter Monday
+	)
+}
+
+func TestCollectionsOn_WeekShiftAfterBankHoliday(t *testing.T) {
+	loc := config.Location{
+		Label:        "Home",
+		HolidayShift: config.HolidayShift{Days: 1, Scope: config.HolidayScopeWeek},
+		CollectionDays: []config.CollectionDay{
+			{Day: time.Tuesday, Types: []string{"Recycling"}, EveryNWeeks: 1},
+			{Day: time.Wednesday, Types: []string{"Garden Waste"}, EveryNWeeks: 1},
+		},
+	}
+	cal := easter2026()
+	day := func(d int) time.Time { return time.Date(2026, 4, d, 0, 0, 0, 0, time.UTC) }
+
+	tue, err := CollectionsOn(loc, day(7), cal)
+	assert.NoError(t, err)
+	assert.Empty(t, tue)
+
+	wed, err := CollectionsOn(loc, day(8), cal)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.CollectionDay{loc.CollectionDays[0]}, wed)
+
+	thu, err := CollectionsOn(loc, day(9), cal)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.CollectionDay{loc.CollectionDays[1]}, thu)
+
+	// The following week is back to normal.
+	nextTue, err := CollectionsOn(loc, day(14), cal)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.CollectionDay{loc.CollectionDays[0]}, nextTue)
+}
+
+func TestCollectionsOn_DayShiftOnlyMovesHolidayCollection(t *testing.T) {
+	loc := config.Location{
+		Label:        "Home",
+		HolidayShift: config.HolidayShift{Days: 1, Scope: config.HolidayScopeDay},
+		CollectionDays: []config.CollectionDay{
+			{Day: time.Monday, Types: []string{"General Waste"}, EveryNWeeks: 1},
+			{Day: time.Tuesday, Types: []string{"Recycling"}, EveryNWeeks: 1},
+		},
+	}
+	cal := easter2026()
+
+	tue, err := CollectionsOn(loc, time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC), cal)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.CollectionDay{loc.CollectionDays[1], loc.CollectionDays[0]}, tue)
+
+	mon, err := CollectionsOn(loc, time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC), cal)
+	assert.NoError(t, err)
+	assert.Empty(t, mon)
+}
+
+func TestCollectionsOn_NoShiftWithoutRule(t *testing.T) {
+	loc := config.Location{
+		CollectionDays: []config.CollectionDay{{Day: time.Monday, Types: []string{"General Waste"}, EveryNWeeks: 1}},
+	}
+
+	days, err := CollectionsOn(loc, time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC), easter2026())
+	assert.NoError(t, err)
+	assert.Len(t, days, 1)
+}
+
+func TestProjectCollections_HonoursHolidayShift(t *testing.T) {
+	locations := []config.Location{
+		{
+			Label:        "Home",
+			HolidayShift: config.HolidayShift{Days: 1},
+			CollectionDays: []config.CollectionDay{
+				{Day: time.Tuesday, Types: []string{"Recycling"}, EveryNWeeks: 1},
+			},
+		},
+	}
+
+	from := time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)
+
+	results := ProjectCollections(locations, from, to, time.UTC, easter2026())
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC), results[0].Date)
+	assert.Equal(t, time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC), results[1].Date)
+}
+
+func festiveLocation() config.Location {
+	return config.Location{
+		Label: "Home",
+		CollectionDays: []config.CollectionDay{
+			{Day: time.Friday, Types: []string{"Recycling", "General Waste"}, EveryNWeeks: 1},
+		},
+		Exceptions: []config.Exception{
+			{From: "2026-12-25", To: "2026-12-28"},
+			{Skip: "2027-01-01", Types: []string{"recycling"}},
+			{Extra: "2027-01-09", Types: []string{"Christmas Trees"}},
+		},
+	}
+}
+
+func TestCollectionsOn_Exceptions(t *testing.T) {
+	loc := festiveLocation()
+	on := func(y int, m time.Month, d int) []config.CollectionDay {
+		days, err := CollectionsOn(loc, time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil)
+		assert.NoError(t, err)
+		return days
+	}
+
+	assert.Empty(t, on(2026, 12, 25), "moved away")
+	assert.Equal(t, []config.CollectionDay{loc.CollectionDays[0]}, on(2026, 12, 28), "moved to")
+
+	skipped := on(2027, 1, 1)
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, []string{"General Waste"}, skipped[0].Types, "skip limited to recycling")
+
+	extra := on(2027, 1, 9)
+	assert.Len(t, extra, 1)
+	assert.Equal(t, time.Saturday, extra[0].Day)
+	assert.Equal(t, []string{"Christmas Trees"}, extra[0].Types)
+
+	assert.Equal(t, []config.CollectionDay{loc.CollectionDays[0]}, on(2027, 1, 8), "normal week unaffected")
+	assert.Equal(t, []string{"Recycling", "General Waste"}, loc.CollectionDays[0].Types, "config not modified")
+}
+
+func TestCollectionsOn_MoveLimitedToTypes(t *testing.T) {
+	loc := config.Location{
+		CollectionDays: []config.CollectionDay{
+			{Day: time.Friday, Types: []string{"Recycling", "General Waste"}, EveryNWeeks: 1},
+		},
+		Exceptions: []config.Exception{{From: "2026-12-25", To: "2026-12-24", Types: []string{"General Waste"}}},
+	}
+
+	from, err := CollectionsOn(loc, time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), nil)
+	assert.NoError(t, err)
+	assert.Len(t, from, 1)
+	assert.Equal(t, []string{"Recycling"}, from[0].Types)
+
+	to, err := CollectionsOn(loc, time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), nil)
+	assert.NoError(t, err)
+	assert.Len(t, to, 1)
+	assert.Equal(t, []string{"General Waste"}, to[0].Types)
+}
+
+func TestProjectCollections_AppliesExceptions(t *testing.T) {
+	from := time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2027, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	results := ProjectCollections([]config.Location{festiveLocation()}, from, to, time.UTC, nil)
+
+	var dates []string
+	for _, c := range results {
+		dates = append(dates, c.Date.Format("2006-01-02"))
+	}
+	assert.Equal(t, []string{"2026-12-28", "2027-01-01", "2027-01-08", "2027-01-09"}, dates)
+	assert.Equal(t, []string{"General Waste"}, results[1].Types)
+	assert.Equal(t, []string{"Christmas Trees"}, results[3].Types)
+}
+
+func TestProjectCollections_RRule(t *testing.T) {
+	locations := []config.Location{{
+		Label: "Home",
+		CollectionDays: []config.CollectionDay{
+			{RRule: "FREQ=MONTHLY;BYDAY=1MO", Types: []string{"Bulky Waste"}},
+		},
+		HolidayShift: config.HolidayShift{Days: 1, Scope: config.HolidayScopeWeek},
+	}}
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)
+	cal := holidays.New(time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC))
+
+	results := ProjectCollections(locations, from, to, time.UTC, cal)
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "2026-04-06", results[0].Date.Format("2006-01-02"))
+	assert.Equal(t, "2026-05-05", results[1].Date.Format("2006-01-02"), "moved by the May bank holiday")
+}
+
+func TestCollectionsOn_InvalidRRule(t *testing.T) {
+	loc := config.Location{CollectionDays: []config.CollectionDay{
+		{RRule: "FREQ=HOURLY", Types: []string{"Recycling"}},
+	}}
+
+	_, err := CollectionsOn(loc, time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC), nil)
+	assert.ErrorContains(t, err, "invalid rrule in collection schedule")
+}
+
+func TestCollectionsOn_ActiveWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, until string
+		date        time.Time
+		expected    bool
+	}{
+		{"in season", "03-01", "11-30", time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), true},
+		{"first day", "03-01", "11-30", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), true},
+		{"winter", "03-01", "11-30", time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"spanning new year", "11-01", "02-28", time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), true},
+		{"outside span", "11-01", "02-28", time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), false},
+		{"only from", "03-01", "", time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC), false},
+		{"before start date", "2026-04-01", "", time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), false},
+		{"after end date", "", "2026-04-01", time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC), false},
+		{"date and month-day", "2026-04-01", "11-30", time.Date(2027, 4, 6, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := config.Location{CollectionDays: []config.CollectionDay{{
+				Day:         tt.date.Weekday(),
+				Types:       []string{"Garden Waste"},
+				EveryNWeeks: 1,
+				ActiveFrom:  tt.from,
+				ActiveUntil: tt.until,
+			}}}
+
+			days, err := CollectionsOn(loc, tt.date, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, len(days) == 1)
+		})
+	}
+}
+
+func TestProjectCollections_ActiveWindow(t *testing.T) {
+	locations := []config.Location{{
+		Label: "Home",
+		CollectionDays: []config.CollectionDay{
+			{Day: time.Monday, Types: []string{"General Waste"}, EveryNWeeks: 1},
+			{Day: time.Monday, Types: []string{"Garden Waste"}, EveryNWeeks: 1, ActiveFrom: "03-01", ActiveUntil: "11-30"},
+		},
+	}}
+	from := time.Date(2026, 11, 23, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 12, 7, 0, 0, 0, 0, time.UTC)
+
+	results := ProjectCollections(locations, from, to, time.UTC, nil)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, "2026-11-30", results[1].Date.Format("2006-01-02"))
+	assert.Equal(t, []string{"General Waste", "Garden Waste"}, results[1].Types)
+	assert.Equal(t, "2026-12-07", results[2].Date.Format("2006-01-02"))
+	assert.Equal(t, []string{"General Waste"}, results[2].Types)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"log"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,13 +11,29 @@ import (
 
 	"github.com/chromedp/chromedp"
 	"github.com/chromedp/chromedp/kb"
+	"github.com/stebennett/bin-notifier/pkg/browser"
 	"github.com/stebennett/bin-notifier/pkg/dateutil"
 	regexputil "github.com/stebennett/bin-notifier/pkg/regexp"
 )
 
-type BracknellScraper struct{}
+func init() {
+	Register(Info{
+		Name:             "bracknell",
+		DisplayName:      "Bracknell Forest Council",
+		PostcodePrefixes: []string{"GU47", "RG12", "RG40", "RG42", "RG45", "SL4", "SL5"},
+		Fields:           addressFields,
+		New: func(opts Options) BinScraper {
+			return &BracknellScraper{loc: opts.Location, browser: opts.Browser}
+		},
+	})
+}
+
+type BracknellScraper struct {
+	loc     *time.Location
+	browser *browser.Pool
+}
 
-func (s *BracknellScraper) ScrapeBinTimes(postCode string, addressCode string) ([]BinTime, error) {
+func (s *BracknellScraper) ScrapeBinTimes(ctx context.Context, postCode string, addressCode string) ([]BinTime, error) {
 	if len(postCode) == 0 {
 		return []BinTime{}, errors.New("no postcode specified")
 	}
@@ -26,33 +41,17 @@ func (s *BracknellScraper) ScrapeBinTimes(postCode string, addressCode string) (
 		return []BinTime{}, errors.New("no address specified")
 	}
 
-	log.Printf("creating temp user data dir")
-	dir, err := os.MkdirTemp("", "chromedp-example")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer os.RemoveAll(dir)
-
-	log.Printf("setting chrome defaults")
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.DisableGPU,
-		chromedp.UserDataDir(dir),
-		chromedp.Flag("headless", true),
-		chromedp.NoSandbox,
-	)
-
-	log.Printf("creating chrome context")
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
-
-	log.Printf("creating logger")
-	taskCtx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
-	defer cancel()
-
 	log.Printf("creating timeout")
-	taskCtx, cancel = context.WithTimeout(taskCtx, 60*time.Second)
+	ctx, cancel := withDefaultTimeout(ctx)
 	defer cancel()
 
+	log.Printf("opening browser tab")
+	taskCtx, release, err := openTab(ctx, s.browser)
+	if err != nil {
+		return []BinTime{}, err
+	}
+	defer release()
+
 	log.Printf("running task")
 	collectionTimes := make([]string, 4)
 
@@ -90,7 +89,7 @@ func (s *BracknellScraper) ScrapeBinTimes(postCode string, addressCode string) (
 
 	binTimes := make([]BinTime, len(collectionTimes))
 	for i, t := range collectionTimes {
-		binTimes[i], err = parseBracknellCollectionTime(t)
+		binTimes[i], err = parseBracknellCollectionTime(t, s.loc)
 		if err != nil {
 			return binTimes, err
 		}
@@ -99,7 +98,7 @@ func (s *BracknellScraper) ScrapeBinTimes(postCode string, addressCode string) (
 	return binTimes, nil
 }
 
-func parseBracknellCollectionTime(times string) (BinTime, error) {
+func parseBracknellCollectionTime(times string, loc *time.Location) (BinTime, error) {
 	t := strings.Split(times, "\n")
 
 	exp := `Your next (?P<BinType>[a-z\s]+) collection is [A-Za-z]+ (?P<Date>\d+) (?P<Month>[A-Za-z]+) (?P<Year>\d{4})`
@@ -113,5 +112,5 @@ func parseBracknellCollectionTime(times string) (BinTime, error) {
 	day, _ := strconv.Atoi(matches["Date"])
 	year, _ := strconv.Atoi(matches["Year"])
 
-	return BinTime{matches["BinType"], dateutil.AsTimeWithMonth(day, matches["Month"], year)}, nil
+	return BinTime{matches["BinType"], dateutil.AsTimeWithMonth(day, matches["Month"], year, loc)}, nil
 }
@@ -0,0 +1,203 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// fetchHTML requests rawURL, sending form as a urlencoded body when set, and
+// parses the response.
+func fetchHTML(ctx context.Context, client *http.Client, method, rawURL string, form url.Values) (*html.Node, error) {
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("User-Agent", "bin-notifier")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, rawURL)
+	}
+	return html.Parse(resp.Body)
+}
+
+// submitForm submits the form containing the button with id buttonID, as if
+// the button were clicked. The form's current fields, including hidden tokens,
+// are sent, with values set for the fields with the given ids. Relative form
+// actions are resolved against pageURL.
+func submitForm(ctx context.Context, client *http.Client, pageURL string, doc *html.Node, buttonID string, values map[string]string) (*html.Node, error) {
+	button := findByID(doc, buttonID)
+	if button == nil {
+		return nil, fmt.Errorf("button #%s not found", buttonID)
+	}
+	form := button.Parent
+	for form != nil && form.Data != "form" {
+		form = form.Parent
+	}
+	if form == nil {
+		return nil, fmt.Errorf("button #%s is not in a form", buttonID)
+	}
+
+	fields := formValues(form)
+	for id, value := range values {
+		field := findByID(form, id)
+		if field == nil {
+			return nil, fmt.Errorf("field #%s not found", id)
+		}
+		if field.Data == "select" && findFirst(field, func(n *html.Node) bool {
+			return n.Data == "option" && attr(n, "value") == value
+		}) == nil {
+			return nil, fmt.Errorf("no option %q in #%s", value, id)
+		}
+		fields.Set(attr(field, "name"), value)
+	}
+	if name := attr(button, "name"); name != "" {
+		fields.Set(name, attr(button, "value"))
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	action, err := base.Parse(attr(form, "action"))
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(attr(form, "method"), http.MethodGet) {
+		action.RawQuery = fields.Encode()
+		return fetchHTML(ctx, client, http.MethodGet, action.String(), nil)
+	}
+	return fetchHTML(ctx, client, http.MethodPost, action.String(), fields)
+}
+
+// formValues returns the values a browser would submit for form's fields,
+// leaving out buttons and unchecked boxes.
+func formValues(form *html.Node) url.Values {
+	values := url.Values{}
+	for _, n := range findAll(form, func(n *html.Node) bool { return attr(n, "name") != "" }) {
+		name := attr(n, "name")
+		switch n.Data {
+		case "input":
+			switch strings.ToLower(attr(n, "type")) {
+			case "submit", "button", "image", "reset", "file":
+			case "checkbox", "radio":
+				if hasAttr(n, "checked") {
+					values.Add(name, attrOr(n, "value", "on"))
+				}
+			default:
+				values.Add(name, attr(n, "value"))
+			}
+		case "select":
+			options := findAll(n, func(o *html.Node) bool { return o.Data == "option" })
+			selected := findFirst(n, func(o *html.Node) bool { return o.Data == "option" && hasAttr(o, "selected") })
+			if selected == nil && len(options) > 0 {
+				selected = options[0]
+			}
+			if selected != nil {
+				values.Add(name, attrOr(selected, "value", textContent(selected)))
+			}
+		case "textarea":
+			values.Add(name, textContent(n))
+		}
+	}
+	return values
+}
+
+// findAll returns the element nodes under n, including n, that match.
+func findAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && match(n) {
+			found = append(found, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return found
+}
+
+// findFirst returns the first element node under n, including n, that matches.
+func findFirst(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findByID(n *html.Node, id string) *html.Node {
+	return findFirst(n, func(n *html.Node) bool { return attr(n, "id") == id })
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	return attrOr(n, key, "")
+}
+
+func attrOr(n *html.Node, key, fallback string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return fallback
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent returns the text under n with runs of whitespace collapsed.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
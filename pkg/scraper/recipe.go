@@ -0,0 +1,355 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/stebennett/bin-notifier/pkg/browser"
+	"github.com/stebennett/bin-notifier/pkg/dateutil"
+	regexputil "github.com/stebennett/bin-notifier/pkg/regexp"
+	"gopkg.in/yaml.v3"
+)
+
+// Recipe describes a council scraper as data rather than Go: the page to open,
+// browser steps that look the address up, and how to read collections from the
+// result. Selectors are CSS selectors.
+type Recipe struct {
+	Name             string        `yaml:"name"`
+	DisplayName      string        `yaml:"display_name"`
+	PostcodePrefixes []string      `yaml:"postcode_prefixes"`
+	URL              string        `yaml:"url"`
+	Steps            []RecipeStep  `yaml:"steps"`
+	Extract          RecipeExtract `yaml:"extract"`
+
+	pattern *regexp.Regexp
+}
+
+// RecipeStep is a browser action run after opening the recipe's URL. URL and
+// Value may contain the {postcode} and {address_code} placeholders.
+type RecipeStep struct {
+	// Action is navigate, click, set_value or wait.
+	Action   string `yaml:"action"`
+	Selector string `yaml:"selector"`
+	Value    string `yaml:"value"`
+	URL      string `yaml:"url"`
+	// Duration makes a wait step sleep instead of waiting for Selector.
+	Duration time.Duration `yaml:"duration"`
+}
+
+// RecipeExtract reads collections from the final page. The text of each
+// element matching Selector is matched against Pattern, whose BinType and Date
+// named groups give the collection; elements that don't match are skipped.
+// Date is parsed with DateLayout, a Go time layout. When the layout has no
+// year the next occurrence of the date is used.
+type RecipeExtract struct {
+	Selector   string `yaml:"selector"`
+	Pattern    string `yaml:"pattern"`
+	DateLayout string `yaml:"date_layout"`
+}
+
+var placeholderExp = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// ParseRecipe reads and validates a YAML recipe.
+func ParseRecipe(data []byte) (*Recipe, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var r Recipe
+	if err := dec.Decode(&r); err != nil {
+		return nil, err
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *Recipe) validate() error {
+	r.Name = strings.ToLower(r.Name)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.DisplayName == "" {
+		r.DisplayName = r.Name
+	}
+	if r.URL == "" {
+		return errors.New("url is required")
+	}
+	if err := checkPlaceholders(r.URL); err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	for i, step := range r.Steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+
+	e := r.Extract
+	if e.Selector == "" || e.Pattern == "" || e.DateLayout == "" {
+		return errors.New("extract needs selector, pattern and date_layout")
+	}
+	pattern, err := regexp.Compile(e.Pattern)
+	if err != nil {
+		return fmt.Errorf("extract: invalid pattern: %w", err)
+	}
+	for _, group := range []string{"BinType", "Date"} {
+		if pattern.SubexpIndex(group) < 0 {
+			return fmt.Errorf("extract: pattern must have a %s named group", group)
+		}
+	}
+	r.pattern = pattern
+	return nil
+}
+
+func (s RecipeStep) validate() error {
+	switch s.Action {
+	case "navigate":
+		if s.URL == "" {
+			return errors.New("navigate needs a url")
+		}
+		return checkPlaceholders(s.URL)
+	case "click":
+		if s.Selector == "" {
+			return errors.New("click needs a selector")
+		}
+	case "set_value":
+		if s.Selector == "" || s.Value == "" {
+			return errors.New("set_value needs a selector and a value")
+		}
+		return checkPlaceholders(s.Value)
+	case "wait":
+		if (s.Selector == "") == (s.Duration == 0) {
+			return errors.New("wait needs either a selector or a duration")
+		}
+		if s.Duration < 0 {
+			return errors.New("wait duration must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown action %q (available: navigate, click, set_value, wait)", s.Action)
+	}
+	return nil
+}
+
+// checkPlaceholders checks that s uses only the placeholders of addressFields.
+func checkPlaceholders(s string) error {
+	for _, m := range placeholderExp.FindAllStringSubmatch(s, -1) {
+		if m[1] != "postcode" && m[1] != "address_code" {
+			return fmt.Errorf("unknown placeholder {%s}", m[1])
+		}
+	}
+	return nil
+}
+
+// fields returns the address fields the recipe's placeholders use.
+func (r *Recipe) fields() []Field {
+	used := r.URL
+	for _, step := range r.Steps {
+		used += step.URL + step.Value
+	}
+	var fields []Field
+	for _, f := range addressFields {
+		if strings.Contains(used, "{"+f.Name+"}") {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Info returns the registry entry for the recipe.
+func (r *Recipe) Info() Info {
+	return Info{
+		Name:             r.Name,
+		DisplayName:      r.DisplayName,
+		PostcodePrefixes: r.PostcodePrefixes,
+		Fields:           r.fields(),
+		New: func(opts Options) BinScraper {
+			return &RecipeScraper{recipe: r, loc: opts.Location, browser: opts.Browser, now: time.Now}
+		},
+		Recipe: true,
+	}
+}
+
+// LoadRecipes parses every .yaml and .yml file in dir as a recipe.
+func LoadRecipes(dir string) ([]*Recipe, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipes []*Recipe
+	files := make(map[string]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		r, err := ParseRecipe(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if other, dup := files[r.Name]; dup {
+			return nil, fmt.Errorf("%s: recipe %q is also defined in %s", path, r.Name, other)
+		}
+		files[r.Name] = path
+		recipes = append(recipes, r)
+	}
+	return recipes, nil
+}
+
+// RegisterRecipes adds recipe scrapers to the registry, replacing any
+// registered by an earlier call. Programs call it once at startup with the
+// recipes of their config. It fails without changing the registry if a
+// recipe's name is taken by a built-in scraper.
+func RegisterRecipes(recipes []*Recipe) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range recipes {
+		if existing, ok := registry[r.Name]; ok && !existing.Recipe {
+			return fmt.Errorf("recipe %q: a built-in scraper has that name", r.Name)
+		}
+	}
+	for name, info := range registry {
+		if info.Recipe {
+			delete(registry, name)
+		}
+	}
+	for _, r := range recipes {
+		registry[r.Name] = r.Info()
+	}
+	return nil
+}
+
+// RecipeScraper scrapes a council website by following a Recipe in headless
+// Chrome.
+type RecipeScraper struct {
+	recipe  *Recipe
+	loc     *time.Location
+	browser *browser.Pool
+	now     func() time.Time
+}
+
+func (s *RecipeScraper) ScrapeBinTimes(ctx context.Context, postCode string, addressCode string) ([]BinTime, error) {
+	values := map[string]string{"postcode": postCode, "address_code": addressCode}
+	for _, f := range s.recipe.fields() {
+		if values[f.Name] != "" {
+			continue
+		}
+		if f.Name == "postcode" {
+			return []BinTime{}, errors.New("no postcode specified")
+		}
+		return []BinTime{}, errors.New("no address specified")
+	}
+	expand := func(s string) string {
+		return placeholderExp.ReplaceAllStringFunc(s, func(p string) string {
+			return values[strings.Trim(p, "{}")]
+		})
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	taskCtx, release, err := openTab(ctx, s.browser)
+	if err != nil {
+		return []BinTime{}, err
+	}
+	defer release()
+
+	log.Printf("running %s recipe", s.recipe.Name)
+	err = chromedp.Run(taskCtx, s.recipe.actions(expand)...)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	log.Printf("extracting collection dates")
+	var texts []string
+	selector, _ := json.Marshal(s.recipe.Extract.Selector)
+	err = chromedp.Run(taskCtx,
+		chromedp.WaitVisible(s.recipe.Extract.Selector, chromedp.ByQuery),
+		chromedp.Evaluate(fmt.Sprintf(`Array.from(document.querySelectorAll(%s)).map(e => e.textContent)`, selector), &texts),
+	)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	return s.recipe.parseCollections(texts, s.loc, s.now())
+}
+
+// actions returns the chromedp actions for the recipe's URL and steps, with
+// placeholders filled in by expand.
+func (r *Recipe) actions(expand func(string) string) []chromedp.Action {
+	actions := []chromedp.Action{chromedp.Navigate(expand(r.URL))}
+	for _, step := range r.Steps {
+		switch step.Action {
+		case "navigate":
+			actions = append(actions, chromedp.Navigate(expand(step.URL)))
+		case "click":
+			actions = append(actions, chromedp.Click(step.Selector, chromedp.ByQuery))
+		case "set_value":
+			// Pages often only react to the value through its change event.
+			selector, _ := json.Marshal(step.Selector)
+			actions = append(actions,
+				chromedp.SetValue(step.Selector, expand(step.Value), chromedp.ByQuery),
+				chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s).dispatchEvent(new Event("change", {bubbles: true}))`, selector), nil),
+			)
+		case "wait":
+			if step.Duration > 0 {
+				actions = append(actions, chromedp.Sleep(step.Duration))
+			} else {
+				actions = append(actions, chromedp.WaitVisible(step.Selector, chromedp.ByQuery))
+			}
+		}
+	}
+	return actions
+}
+
+// parseCollections reads a collection from each extracted text that matches
+// the recipe's pattern. now places dates whose layout has no year.
+func (r *Recipe) parseCollections(texts []string, loc *time.Location, now time.Time) ([]BinTime, error) {
+	var binTimes []BinTime
+	for _, text := range texts {
+		text = strings.Join(strings.Fields(text), " ")
+		matches := regexputil.FindNamedMatches(r.pattern, text)
+		if len(matches) == 0 {
+			continue
+		}
+
+		date, err := time.Parse(r.Extract.DateLayout, strings.TrimSpace(matches["Date"]))
+		if err != nil {
+			return binTimes, fmt.Errorf("failed to parse date %q: %w", matches["Date"], err)
+		}
+		year := date.Year()
+		if year == 0 {
+			today := dateutil.Today(now, loc)
+			year = today.Year()
+			// Pages list upcoming collections, so a date long gone is next year's.
+			if dateutil.AsTime(date.Day(), int(date.Month()), year, loc).Before(today.AddDate(0, -1, 0)) {
+				year++
+			}
+		}
+
+		binTimes = append(binTimes, BinTime{
+			Type:           strings.TrimSpace(matches["BinType"]),
+			CollectionTime: dateutil.AsTime(date.Day(), int(date.Month()), year, loc),
+		})
+	}
+	if len(binTimes) == 0 {
+		return []BinTime{}, errors.New("no collections found")
+	}
+	return binTimes, nil
+}
@@ -0,0 +1,212 @@
+package scraper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRecipe = `
+name: Testshire
+display_name: Testshire District Council
+postcode_prefixes: [TE1, TE2]
+url: https://bins.testshire.gov.uk/lookup?postcode={postcode}
+steps:
+  - action: wait
+    selector: "select#address"
+  - action: set_value
+    selector: "select#address"
+    value: "{address_code}"
+  - action: click
+    selector: "button[type=submit]"
+  - action: wait
+    duration: 2s
+extract:
+  selector: "table.collections tr"
+  pattern: '(?P<BinType>[A-Za-z ]+?) collection (?P<Date>\w+ \d+ \w+ \d{4})'
+  date_layout: "Monday 2 January 2006"
+`
+
+func TestParseRecipe(t *testing.T) {
+	r, err := ParseRecipe([]byte(testRecipe))
+	require.NoError(t, err)
+
+	assert.Equal(t, "testshire", r.Name)
+	assert.Len(t, r.Steps, 4)
+	assert.Equal(t, 2*time.Second, r.Steps[3].Duration)
+	assert.Len(t, r.actions(func(s string) string { return s }), 6, "navigate, four steps and a change event")
+
+	info := r.Info()
+	assert.Equal(t, "Testshire District Council", info.DisplayName)
+	assert.True(t, info.SupportsPostcode("TE1 1AA"))
+	assert.Equal(t, addressFields, info.Fields)
+}
+
+func TestParseRecipe_FieldsFromPlaceholders(t *testing.T) {
+	r, err := ParseRecipe([]byte(`
+name: uprn-only
+url: https://example.com/bins/{address_code}
+extract:
+  selector: li
+  pattern: '(?P<BinType>\w+): (?P<Date>\d{2}/\d{2}/\d{4})'
+  date_layout: 02/01/2006
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "uprn-only", r.Info().DisplayName)
+	assert.Equal(t, []Field{addressFields[1]}, r.Info().Fields)
+
+	_, err = r.Info().New(Options{Location: time.UTC}).ScrapeBinTimes(context.Background(), "", "")
+	assert.EqualError(t, err, "no address specified")
+}
+
+func TestParseRecipe_Errors(t *testing.T) {
+	const extract = `
+extract:
+  selector: li
+  pattern: '(?P<BinType>\w+): (?P<Date>.+)'
+  date_layout: 02/01/2006`
+
+	tests := []struct {
+		name   string
+		recipe string
+		errMsg string
+	}{
+		{"no name", "url: https://example.com" + extract, "name is required"},
+		{"no url", "name: a" + extract, "url is required"},
+		{"unknown field", "name: a\nurl: https://example.com\nwait: 1s" + extract, "yaml: unmarshal errors:\n  line 3: field wait not found in type scraper.Recipe"},
+		{"bad placeholder", "name: a\nurl: https://example.com/{uprn}" + extract, "url: unknown placeholder {uprn}"},
+		{"unknown action", "name: a\nurl: https://example.com\nsteps:\n  - action: type" + extract, `step 1: unknown action "type" (available: navigate, click, set_value, wait)`},
+		{"click without selector", "name: a\nurl: https://example.com\nsteps:\n  - action: click" + extract, "step 1: click needs a selector"},
+		{"navigate without url", "name: a\nurl: https://example.com\nsteps:\n  - action: navigate" + extract, "step 1: navigate needs a url"},
+		{"set_value without value", "name: a\nurl: https://example.com\nsteps:\n  - action: set_value\n    selector: input" + extract, "step 1: set_value needs a selector and a value"},
+		{"wait with both", "name: a\nurl: https://example.com\nsteps:\n  - action: wait\n    selector: input\n    duration: 1s" + extract, "step 1: wait needs either a selector or a duration"},
+		{"no extract", "name: a\nurl: https://example.com", "extract needs selector, pattern and date_layout"},
+		{"bad pattern", "name: a\nurl: https://example.com\nextract:\n  selector: li\n  pattern: '(?P<BinType>'\n  date_layout: 2006", "extract: invalid pattern: error parsing regexp: missing closing ): `(?P<BinType>`"},
+		{"missing group", "name: a\nurl: https://example.com\nextract:\n  selector: li\n  pattern: '(?P<BinType>\\w+)'\n  date_layout: 2006", "extract: pattern must have a Date named group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRecipe([]byte(tt.recipe))
+			require.Error(t, err)
+			assert.Equal(t, tt.errMsg, err.Error())
+		})
+	}
+}
+
+func TestLoadRecipes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "testshire.yaml"), []byte(testRecipe), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a recipe"), 0o644))
+
+	recipes, err := LoadRecipes(dir)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "testshire", recipes[0].Name)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copy.yml"), []byte(testRecipe), 0o644))
+	_, err = LoadRecipes(dir)
+	assert.EqualError(t, err, filepath.Join(dir, "testshire.yaml")+`: recipe "testshire" is also defined in `+filepath.Join(dir, "copy.yml"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "copy.yml"), []byte("name: broken"), 0o644))
+	_, err = LoadRecipes(dir)
+	assert.EqualError(t, err, filepath.Join(dir, "copy.yml")+": url is required")
+
+	_, err = LoadRecipes(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestRegisterRecipes(t *testing.T) {
+	t.Cleanup(func() { RegisterRecipes(nil) })
+
+	r, err := ParseRecipe([]byte(testRecipe))
+	require.NoError(t, err)
+	require.NoError(t, RegisterRecipes([]*Recipe{r}))
+
+	s, err := NewScraper("Testshire", Options{})
+	require.NoError(t, err)
+	assert.Same(t, r, s.(*RecipeScraper).recipe)
+	assert.Contains(t, Names(), "testshire")
+
+	// Registering again replaces the earlier recipes.
+	require.NoError(t, RegisterRecipes([]*Recipe{r}))
+	require.NoError(t, RegisterRecipes(nil))
+	_, ok := Lookup("testshire")
+	assert.False(t, ok)
+
+	clash := *r
+	clash.Name = "bracknell"
+	err = RegisterRecipes([]*Recipe{r, &clash})
+	assert.EqualError(t, err, `recipe "bracknell": a built-in scraper has that name`)
+	_, ok = Lookup("testshire")
+	assert.False(t, ok, "registry is unchanged on error")
+	info, _ := Lookup("bracknell")
+	assert.Equal(t, "Bracknell Forest Council", info.DisplayName)
+}
+
+func TestRecipe_ParseCollections(t *testing.T) {
+	r, err := ParseRecipe([]byte(testRecipe))
+	require.NoError(t, err)
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	binTimes, err := r.parseCollections([]string{
+		"Bin Next collection",
+		"\n  General waste collection\n  Tuesday 16 June 2026 ",
+		"Recycling collection Tuesday 23 June 2026",
+	}, london, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []BinTime{
+		{Type: "General waste", CollectionTime: time.Date(2026, 6, 16, 0, 0, 0, 0, london)},
+		{Type: "Recycling", CollectionTime: time.Date(2026, 6, 23, 0, 0, 0, 0, london)},
+	}, binTimes)
+
+	_, err = r.parseCollections([]string{"Bin Next collection"}, london, time.Now())
+	assert.EqualError(t, err, "no collections found")
+
+	_, err = r.parseCollections([]string{"Recycling collection Someday 31 June 2026"}, london, time.Now())
+	assert.ErrorContains(t, err, `failed to parse date "Someday 31 June 2026"`)
+}
+
+func TestRecipe_ParseCollectionsWithoutYear(t *testing.T) {
+	r, err := ParseRecipe([]byte(`
+name: no-year
+url: https://example.com/{postcode}/{address_code}
+extract:
+  selector: li
+  pattern: '(?P<BinType>[A-Za-z ]+): \w+ (?P<Date>\d+ \w+)'
+  date_layout: 2 January
+`))
+	require.NoError(t, err)
+
+	now := time.Date(2026, 12, 28, 9, 0, 0, 0, time.UTC)
+	binTimes, err := r.parseCollections([]string{
+		"Recycling: Tuesday 29 December",
+		"Garden waste: Tuesday 5 January",
+		"Food waste: Thursday 10 December",
+	}, time.UTC, now)
+	require.NoError(t, err)
+	assert.Equal(t, []BinTime{
+		{Type: "Recycling", CollectionTime: time.Date(2026, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{Type: "Garden waste", CollectionTime: time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{Type: "Food waste", CollectionTime: time.Date(2026, 12, 10, 0, 0, 0, 0, time.UTC)},
+	}, binTimes)
+}
+
+func TestRecipeScrapeBinTimes_ValidationErrors(t *testing.T) {
+	r, err := ParseRecipe([]byte(testRecipe))
+	require.NoError(t, err)
+	scraper := r.Info().New(Options{Location: time.UTC})
+
+	_, err = scraper.ScrapeBinTimes(context.Background(), "", "123")
+	assert.EqualError(t, err, "no postcode specified")
+
+	_, err = scraper.ScrapeBinTimes(context.Background(), "TE1 1AA", "")
+	assert.EqualError(t, err, "no address specified")
+}
@@ -1,9 +1,14 @@
 package scraper
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/stebennett/bin-notifier/pkg/browser"
 )
 
 type BinTime struct {
@@ -11,17 +16,213 @@ type BinTime struct {
 	CollectionTime time.Time
 }
 
+// DefaultTimeout bounds a scrape whose context has no deadline.
+const DefaultTimeout = 60 * time.Second
+
+// BinScraper scrapes a council website for an address's upcoming collections.
+// Implementations stop when ctx is cancelled or its deadline passes.
 type BinScraper interface {
+	ScrapeBinTimes(ctx context.Context, postcode string, addressCode string) ([]BinTime, error)
+}
+
+// LegacyScraper is a scraper written before scrapes took a context.
+type LegacyScraper interface {
 	ScrapeBinTimes(postcode string, addressCode string) ([]BinTime, error)
 }
 
-func NewScraper(name string) (BinScraper, error) {
-	switch strings.ToLower(name) {
-	case "bracknell":
-		return &BracknellScraper{}, nil
-	case "wokingham":
-		return &WokinghamScraper{}, nil
-	default:
+// FromLegacy adapts a LegacyScraper to BinScraper. When ctx is done first the
+// scrape is abandoned and ctx's error returned, though the legacy scraper
+// itself keeps running until it finishes.
+func FromLegacy(s LegacyScraper) BinScraper {
+	return legacyScraper{s}
+}
+
+type legacyScraper struct {
+	scraper LegacyScraper
+}
+
+func (l legacyScraper) ScrapeBinTimes(ctx context.Context, postcode string, addressCode string) ([]BinTime, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		binTimes []BinTime
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		binTimes, err := l.scraper.ScrapeBinTimes(postcode, addressCode)
+		done <- result{binTimes, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.binTimes, r.err
+	}
+}
+
+// openTab opens a browser tab for a scrape from pool, or from a browser started
+// for this scrape alone when pool is nil. Call release once the scrape is done.
+func openTab(ctx context.Context, pool *browser.Pool) (tabCtx context.Context, release func(), err error) {
+	if pool != nil {
+		return pool.Tab(ctx)
+	}
+	own := browser.New(1)
+	tabCtx, release, err = own.Tab(ctx)
+	if err != nil {
+		own.Close()
+		return nil, nil, err
+	}
+	return tabCtx, func() {
+		release()
+		own.Close()
+	}, nil
+}
+
+// WithTimeout limits a scrape to timeout, when it is positive. Callers pass a
+// location's scrape_timeout.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withDefaultTimeout applies DefaultTimeout to ctx unless it already has a
+// deadline.
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultTimeout)
+}
+
+// Options configures a scraper created by NewScraper.
+type Options struct {
+	// Location is the time zone collection dates are returned in, as midnight.
+	Location *time.Location
+	// Browser is the Chrome pool chromedp-based scrapers open tabs in. When
+	// nil, each scrape starts and stops its own browser.
+	Browser *browser.Pool
+}
+
+// Field is a location config field a scraper uses.
+type Field struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// Info describes a council scraper in the registry.
+type Info struct {
+	// Name is the value of a location's scraper field, e.g. "bracknell".
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	// PostcodePrefixes are the postcode districts the council covers.
+	PostcodePrefixes []string                      `json:"postcode_prefixes"`
+	Fields           []Field                       `json:"fields"`
+	New              func(opts Options) BinScraper `json:"-"`
+	// Recipe is set for scrapers defined by a YAML recipe rather than in Go.
+	Recipe bool `json:"recipe"`
+}
+
+// SupportsPostcode reports whether postcode is in one of the scraper's postcode
+// districts. A scraper without postcode districts supports any postcode.
+func (i Info) SupportsPostcode(postcode string) bool {
+	if len(i.PostcodePrefixes) == 0 {
+		return true
+	}
+	district := postcodeDistrict(postcode)
+	for _, p := range i.PostcodePrefixes {
+		if district == p {
+			return true
+		}
+	}
+	return false
+}
+
+// postcodeDistrict returns the outward code of a full postcode, e.g. RG12 for
+// "rg121ab". The inward code is always the last three characters, so this
+// works with or without the space.
+func postcodeDistrict(postcode string) string {
+	compact := strings.ToUpper(strings.Join(strings.Fields(postcode), ""))
+	if len(compact) <= 3 {
+		return compact
+	}
+	return compact[:len(compact)-3]
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Info)
+)
+
+// Register adds a scraper to the registry. Scrapers register themselves from
+// an init function. Register panics if the name is empty or already taken.
+func Register(info Info) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := strings.ToLower(info.Name)
+	if name == "" || info.New == nil {
+		panic("scraper: Register needs a name and a constructor")
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("scraper: Register called twice for %q", name))
+	}
+	info.Name = name
+	registry[name] = info
+}
+
+// Lookup returns the registered scraper with the given name, ignoring case.
+func Lookup(name string) (Info, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	info, ok := registry[strings.ToLower(name)]
+	return info, ok
+}
+
+// List returns every registered scraper, sorted by name.
+func List() []Info {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := make([]Info, 0, len(registry))
+	for _, info := range registry {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Names returns the names of every registered scraper, sorted.
+func Names() []string {
+	var names []string
+	for _, info := range List() {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+// NewScraper creates the named council scraper from the registry.
+func NewScraper(name string, opts Options) (BinScraper, error) {
+	info, ok := Lookup(name)
+	if !ok {
 		return nil, fmt.Errorf("unknown scraper: %q", name)
 	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return info.New(opts), nil
+}
+
+// addressFields are the location fields used by scrapers that look an address
+// up by postcode and the council's address code.
+var addressFields = []Field{
+	{Name: "postcode", Description: "Postcode to look up on the council website", Required: true},
+	{Name: "address_code", Description: "The council website's code for the address", Required: true},
 }
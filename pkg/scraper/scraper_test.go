@@ -1,31 +1,180 @@
 package scraper
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/stebennett/bin-notifier/pkg/browser"
 	"github.com/stebennett/bin-notifier/pkg/dateutil"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewScraper_Bracknell(t *testing.T) {
-	s, err := NewScraper("bracknell")
+	s, err := NewScraper("bracknell", Options{Location: time.UTC})
 	assert.NoError(t, err)
 	assert.IsType(t, &BracknellScraper{}, s)
 }
 
 func TestNewScraper_UnknownReturnsError(t *testing.T) {
-	_, err := NewScraper("unknown_council")
+	_, err := NewScraper("unknown_council", Options{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown scraper")
 }
 
 func TestNewScraper_CaseInsensitive(t *testing.T) {
-	s, err := NewScraper("Bracknell")
+	s, err := NewScraper("Bracknell", Options{Location: time.UTC})
 	assert.NoError(t, err)
 	assert.IsType(t, &BracknellScraper{}, s)
 }
 
+func TestNewScraper_UsesLocation(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	assert.NoError(t, err)
+
+	s, err := NewScraper("wokingham", Options{Location: london})
+	assert.NoError(t, err)
+	assert.Equal(t, london, s.(*WokinghamScraper).loc)
+
+	s, err = NewScraper("wokingham", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, s.(*WokinghamScraper).loc)
+}
+
+func TestNewScraper_UsesBrowserPool(t *testing.T) {
+	pool := browser.New(1)
+	defer pool.Close()
+
+	s, err := NewScraper("bracknell", Options{Browser: pool})
+	assert.NoError(t, err)
+	assert.Same(t, pool, s.(*BracknellScraper).browser)
+}
+
+func TestOpenTab_ClosedPoolReturnsError(t *testing.T) {
+	pool := browser.New(1)
+	pool.Close()
+
+	_, _, err := openTab(context.Background(), pool)
+	assert.ErrorIs(t, err, browser.ErrClosed)
+}
+
+func TestList_ReturnsRegisteredScrapersSorted(t *testing.T) {
+	assert.Equal(t, []string{"bracknell", "wokingham", "wokingham-chrome"}, Names())
+
+	infos := List()
+	assert.Equal(t, "Bracknell Forest Council", infos[0].DisplayName)
+	assert.Equal(t, "Wokingham Borough Council", infos[1].DisplayName)
+	assert.Equal(t, infos[1].PostcodePrefixes, infos[2].PostcodePrefixes)
+	assert.Equal(t, []string{"postcode", "address_code"}, []string{infos[0].Fields[0].Name, infos[0].Fields[1].Name})
+}
+
+func TestLookup(t *testing.T) {
+	info, ok := Lookup("Wokingham")
+	assert.True(t, ok)
+	assert.Equal(t, "wokingham", info.Name)
+
+	_, ok = Lookup("unknown_council")
+	assert.False(t, ok)
+}
+
+func TestRegister_PanicsOnDuplicateOrInvalid(t *testing.T) {
+	newScraper := func(Options) BinScraper { return &BracknellScraper{} }
+
+	assert.Panics(t, func() { Register(Info{Name: "Bracknell", New: newScraper}) })
+	assert.Panics(t, func() { Register(Info{Name: "", New: newScraper}) })
+	assert.Panics(t, func() { Register(Info{Name: "nowhere"}) })
+}
+
+func TestInfo_SupportsPostcode(t *testing.T) {
+	info, _ := Lookup("bracknell")
+
+	assert.True(t, info.SupportsPostcode("RG12 1AB"))
+	assert.True(t, info.SupportsPostcode(" rg42 2xy"))
+	assert.True(t, info.SupportsPostcode("RG121AB"))
+	assert.True(t, info.SupportsPostcode("SL4 2AB"))
+	assert.False(t, info.SupportsPostcode("RG1 2AB"))
+	assert.False(t, info.SupportsPostcode("RG12AB"))
+	assert.False(t, info.SupportsPostcode(""))
+
+	assert.True(t, Info{Name: "anywhere"}.SupportsPostcode("AB1 2CD"), "no districts means any postcode")
+}
+
+type stubLegacyScraper struct {
+	binTimes []BinTime
+	err      error
+	release  chan struct{}
+}
+
+func (s *stubLegacyScraper) ScrapeBinTimes(postcode string, addressCode string) ([]BinTime, error) {
+	if s.release != nil {
+		<-s.release
+	}
+	return s.binTimes, s.err
+}
+
+func TestFromLegacy_ReturnsResult(t *testing.T) {
+	binTimes := []BinTime{{Type: "Recycling", CollectionTime: dateutil.AsTime(16, 6, 2026, time.UTC)}}
+	s := FromLegacy(&stubLegacyScraper{binTimes: binTimes})
+
+	actual, err := s.ScrapeBinTimes(context.Background(), "RG12 1AB", "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, binTimes, actual)
+
+	s = FromLegacy(&stubLegacyScraper{err: errors.New("site down")})
+	_, err = s.ScrapeBinTimes(context.Background(), "RG12 1AB", "12345")
+	assert.EqualError(t, err, "site down")
+}
+
+func TestFromLegacy_StopsWaitingWhenContextDone(t *testing.T) {
+	legacy := &stubLegacyScraper{release: make(chan struct{})}
+	defer close(legacy.release)
+	s := FromLegacy(legacy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.ScrapeBinTimes(ctx, "RG12 1AB", "12345")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.ScrapeBinTimes(cancelled, "RG12 1AB", "12345")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	ctx, cancel := withDefaultTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(DefaultTimeout), deadline, time.Second)
+
+	parent, cancelParent := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelParent()
+	ctx, cancel = withDefaultTimeout(parent)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	parentDeadline, _ := parent.Deadline()
+	assert.Equal(t, parentDeadline, deadline)
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx, cancel := WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(90*time.Second), deadline, time.Second)
+
+	ctx, cancel = WithTimeout(context.Background(), 0)
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+	assert.Error(t, ctx.Err())
+}
+
 func TestParseNextCollectionTime(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -37,40 +186,54 @@ func TestParseNextCollectionTime(t *testing.T) {
 			input: `Your next food collection is Monday 26 February 2024
 						Your second collection is Monday 26 February 2024
 						Your third collection is Monday 4 March 2024`,
-			expected: BinTime{"food", dateutil.AsTime(26, 2, 2024)},
+			expected: BinTime{"food", dateutil.AsTime(26, 2, 2024, time.UTC)},
 		},
 		{
 			name: "recycling",
 			input: `Your next recycling collection is Monday 2 February 2024
 						Your second collection is Monday 4 March 2024
 						Your second collection is Monday 18 March 2024`,
-			expected: BinTime{"recycling", dateutil.AsTime(2, 2, 2024)},
+			expected: BinTime{"recycling", dateutil.AsTime(2, 2, 2024, time.UTC)},
 		},
 		{
 			name: "garden",
 			input: `Your next garden collection is Monday 19 February 2024
 						Your second collection is Monday 4 March 2024
 						Your third collection is Monday 18 March 2024`,
-			expected: BinTime{"garden", dateutil.AsTime(19, 2, 2024)},
+			expected: BinTime{"garden", dateutil.AsTime(19, 2, 2024, time.UTC)},
 		},
 		{
 			name: "refuse",
 			input: `Your next refuse collection is Monday 26 February 2024
 						Your second collection is Monday 18 March 2024
 						Your third collection is Monday 4 April 2024`,
-			expected: BinTime{"refuse", dateutil.AsTime(26, 2, 2024)},
+			expected: BinTime{"refuse", dateutil.AsTime(26, 2, 2024, time.UTC)},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := parseBracknellCollectionTime(test.input)
+			actual, err := parseBracknellCollectionTime(test.input, time.UTC)
 			assert.NoError(t, err)
 			assert.Equal(t, test.expected, actual)
 		})
 	}
 }
 
+func TestParseCollectionDates_InLocation(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	assert.NoError(t, err)
+
+	bracknell, err := parseBracknellCollectionTime("Your next food collection is Tuesday 16 June 2026", london)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 6, 16, 0, 0, 0, 0, london), bracknell.CollectionTime)
+
+	wokingham, err := parseWokinghamCollection("Recycling", "Tuesday 16/06/2026", london)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 6, 16, 0, 0, 0, 0, london), wokingham.CollectionTime)
+	assert.True(t, dateutil.IsDateMatching(wokingham.CollectionTime, dateutil.Today(time.Date(2026, 6, 15, 23, 30, 0, 0, time.UTC), london)))
+}
+
 func TestParseNextCollectionTime_Errors(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -104,7 +267,7 @@ func TestParseNextCollectionTime_Errors(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			_, err := parseBracknellCollectionTime(test.input)
+			_, err := parseBracknellCollectionTime(test.input, time.UTC)
 			assert.Error(t, err)
 			assert.EqualError(t, err, "failed to parse next collection time")
 		})
@@ -112,31 +275,154 @@ func TestParseNextCollectionTime_Errors(t *testing.T) {
 }
 
 func TestNewScraper_Wokingham(t *testing.T) {
-	s, err := NewScraper("wokingham")
+	s, err := NewScraper("wokingham", Options{Location: time.UTC})
 	assert.NoError(t, err)
 	assert.IsType(t, &WokinghamScraper{}, s)
 }
 
+func TestNewScraper_WokinghamChrome(t *testing.T) {
+	pool := browser.New(1)
+	defer pool.Close()
+
+	s, err := NewScraper("wokingham-chrome", Options{Location: time.UTC, Browser: pool})
+	assert.NoError(t, err)
+	assert.IsType(t, &WokinghamChromeScraper{}, s)
+	assert.Same(t, pool, s.(*WokinghamChromeScraper).browser)
+}
+
 func TestWokinghamScrapeBinTimes_ValidationErrors(t *testing.T) {
-	scraper := &WokinghamScraper{}
+	scrapers := map[string]BinScraper{
+		"http":   &WokinghamScraper{},
+		"chrome": &WokinghamChromeScraper{},
+	}
 
-	t.Run("empty postcode returns error", func(t *testing.T) {
-		_, err := scraper.ScrapeBinTimes("", "123")
-		assert.Error(t, err)
-		assert.EqualError(t, err, "no postcode specified")
-	})
+	for name, scraper := range scrapers {
+		t.Run(name+" empty postcode returns error", func(t *testing.T) {
+			_, err := scraper.ScrapeBinTimes(context.Background(), "", "123")
+			assert.Error(t, err)
+			assert.EqualError(t, err, "no postcode specified")
+		})
 
-	t.Run("empty address returns error", func(t *testing.T) {
-		_, err := scraper.ScrapeBinTimes("AB1 2CD", "")
-		assert.Error(t, err)
-		assert.EqualError(t, err, "no address specified")
-	})
+		t.Run(name+" empty address returns error", func(t *testing.T) {
+			_, err := scraper.ScrapeBinTimes(context.Background(), "AB1 2CD", "")
+			assert.Error(t, err)
+			assert.EqualError(t, err, "no address specified")
+		})
 
-	t.Run("both empty returns postcode error first", func(t *testing.T) {
-		_, err := scraper.ScrapeBinTimes("", "")
-		assert.Error(t, err)
-		assert.EqualError(t, err, "no postcode specified")
-	})
+		t.Run(name+" both empty returns postcode error first", func(t *testing.T) {
+			_, err := scraper.ScrapeBinTimes(context.Background(), "", "")
+			assert.Error(t, err)
+			assert.EqualError(t, err, "no postcode specified")
+		})
+	}
+}
+
+const wokinghamPostcodePage = `<html><body>
+<form action="/find-your-bin-collection-day" method="post" id="waste-form">
+  <input type="hidden" name="form_build_id" value="form-step1">
+  <input type="hidden" name="form_id" value="waste_collection_form">
+  <input type="text" id="edit-postcode-search" name="postcode_search" value="">
+  <input type="submit" id="edit-find-address" name="op" value="Find address">
+</form>
+</body></html>`
+
+const wokinghamAddressPage = `<html><body>
+<form action="/find-your-bin-collection-day" method="post" id="waste-form">
+  <input type="hidden" name="form_build_id" value="form-step2">
+  <input type="hidden" name="form_id" value="waste_collection_form">
+  <input type="text" id="edit-postcode-search" name="postcode_search" value="RG40 1AA">
+  <select id="edit-address-options" name="address_options">
+    <option value="">Select an address</option>
+    <option value="100">1 High Street</option>
+    <option value="101">2 High Street</option>
+  </select>
+  <input type="submit" id="edit-show-collection-dates" name="op" value="Show collection dates">
+</form>
+</body></html>`
+
+const wokinghamResultsPage = `<html><body>
+<div class="cards-list">
+  <div class="card card--waste">
+    <h3 class="heading">
+      Household waste (week 1)
+    </h3>
+    <span class="card__date">Tuesday
+      16/06/2026</span>
+  </div>
+  <div class="card card--waste">
+    <h3>Recycling</h3>
+    <span class="card__date">Tuesday 23/06/2026</span>
+  </div>
+</div>
+</body></html>`
+
+// wokinghamServer serves the three pages of the Wokingham form, recording the
+// fields posted at each step.
+func wokinghamServer(t *testing.T, posts *[]url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			http.SetCookie(w, &http.Cookie{Name: "SESS", Value: "abc"})
+			w.Write([]byte(wokinghamPostcodePage))
+			return
+		}
+		cookie, err := r.Cookie("SESS")
+		if !assert.NoError(t, err, "session cookie is sent back") || !assert.Equal(t, "abc", cookie.Value) {
+			http.Error(w, "no session", http.StatusForbidden)
+			return
+		}
+		assert.NoError(t, r.ParseForm())
+		*posts = append(*posts, r.PostForm)
+		switch r.PostForm.Get("op") {
+		case "Find address":
+			w.Write([]byte(wokinghamAddressPage))
+		case "Show collection dates":
+			w.Write([]byte(wokinghamResultsPage))
+		default:
+			http.Error(w, "unknown op", http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestWokinghamScrapeBinTimes_SubmitsForm(t *testing.T) {
+	var posts []url.Values
+	server := wokinghamServer(t, &posts)
+	defer server.Close()
+
+	scraper := &WokinghamScraper{loc: time.UTC, pageURL: server.URL + "/find-your-bin-collection-day"}
+	binTimes, err := scraper.ScrapeBinTimes(context.Background(), "RG40 1AA", "101")
+	assert.NoError(t, err)
+	assert.Equal(t, []BinTime{
+		{Type: "Household waste", CollectionTime: time.Date(2026, 6, 16, 0, 0, 0, 0, time.UTC)},
+		{Type: "Recycling", CollectionTime: time.Date(2026, 6, 23, 0, 0, 0, 0, time.UTC)},
+	}, binTimes)
+
+	if assert.Len(t, posts, 2) {
+		assert.Equal(t, "form-step1", posts[0].Get("form_build_id"))
+		assert.Equal(t, "RG40 1AA", posts[0].Get("postcode_search"))
+		assert.Equal(t, "form-step2", posts[1].Get("form_build_id"), "token from the address step is sent")
+		assert.Equal(t, "101", posts[1].Get("address_options"))
+		assert.Equal(t, "waste_collection_form", posts[1].Get("form_id"))
+	}
+}
+
+func TestWokinghamScrapeBinTimes_UnknownAddress(t *testing.T) {
+	var posts []url.Values
+	server := wokinghamServer(t, &posts)
+	defer server.Close()
+
+	scraper := &WokinghamScraper{loc: time.UTC, pageURL: server.URL + "/find-your-bin-collection-day"}
+	_, err := scraper.ScrapeBinTimes(context.Background(), "RG40 1AA", "999")
+	assert.EqualError(t, err, `no option "999" in #edit-address-options`)
+	assert.Len(t, posts, 1)
+}
+
+func TestWokinghamScrapeBinTimes_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	scraper := &WokinghamScraper{loc: time.UTC, pageURL: server.URL}
+	_, err := scraper.ScrapeBinTimes(context.Background(), "RG40 1AA", "101")
+	assert.EqualError(t, err, "unexpected status 404 from "+server.URL)
 }
 
 func TestParseWokinghamCollection(t *testing.T) {
@@ -152,41 +438,41 @@ func TestParseWokinghamCollection(t *testing.T) {
 			heading:      "Household waste (week 2)",
 			dateText:     "Today 27/02/2026",
 			expectedType: "Household waste",
-			expectedTime: dateutil.AsTime(27, 2, 2026),
+			expectedTime: dateutil.AsTime(27, 2, 2026, time.UTC),
 		},
 		{
 			name:         "garden waste with week",
 			heading:      "Garden waste (week 2)",
 			dateText:     "Tuesday 10/03/2026",
 			expectedType: "Garden waste",
-			expectedTime: dateutil.AsTime(10, 3, 2026),
+			expectedTime: dateutil.AsTime(10, 3, 2026, time.UTC),
 		},
 		{
 			name:         "recycling with week",
 			heading:      "Recycling (week 1)",
 			dateText:     "Friday 06/03/2026",
 			expectedType: "Recycling",
-			expectedTime: dateutil.AsTime(6, 3, 2026),
+			expectedTime: dateutil.AsTime(6, 3, 2026, time.UTC),
 		},
 		{
 			name:         "food waste no week",
 			heading:      "Food waste",
 			dateText:     "Today 27/02/2026",
 			expectedType: "Food waste",
-			expectedTime: dateutil.AsTime(27, 2, 2026),
+			expectedTime: dateutil.AsTime(27, 2, 2026, time.UTC),
 		},
 		{
 			name:         "type without week info",
 			heading:      "Recycling",
 			dateText:     "Monday 15/04/2026",
 			expectedType: "Recycling",
-			expectedTime: dateutil.AsTime(15, 4, 2026),
+			expectedTime: dateutil.AsTime(15, 4, 2026, time.UTC),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := parseWokinghamCollection(test.heading, test.dateText)
+			actual, err := parseWokinghamCollection(test.heading, test.dateText, time.UTC)
 			assert.NoError(t, err)
 			assert.Equal(t, test.expectedType, actual.Type)
 			assert.Equal(t, test.expectedTime, actual.CollectionTime)
@@ -229,7 +515,7 @@ func TestParseWokinghamCollection_Errors(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			_, err := parseWokinghamCollection(test.heading, test.dateText)
+			_, err := parseWokinghamCollection(test.heading, test.dateText, time.UTC)
 			assert.Error(t, err)
 		})
 	}
@@ -239,19 +525,19 @@ func TestScrapeBinTimes_ValidationErrors(t *testing.T) {
 	scraper := &BracknellScraper{}
 
 	t.Run("empty postcode returns error", func(t *testing.T) {
-		_, err := scraper.ScrapeBinTimes("", "123")
+		_, err := scraper.ScrapeBinTimes(context.Background(), "", "123")
 		assert.Error(t, err)
 		assert.EqualError(t, err, "no postcode specified")
 	})
 
 	t.Run("empty address returns error", func(t *testing.T) {
-		_, err := scraper.ScrapeBinTimes("AB1 2CD", "")
+		_, err := scraper.ScrapeBinTimes(context.Background(), "AB1 2CD", "")
 		assert.Error(t, err)
 		assert.EqualError(t, err, "no address specified")
 	})
 
 	t.Run("both empty returns postcode error first", func(t *testing.T) {
-		_, err := scraper.ScrapeBinTimes("", "")
+		_, err := scraper.ScrapeBinTimes(context.Background(), "", "")
 		assert.Error(t, err)
 		assert.EqualError(t, err, "no postcode specified")
 	})
@@ -4,19 +4,43 @@ import (
 	"context"
 	"errors"
 	"log"
-	"os"
+	"net/http"
+	"net/http/cookiejar"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/chromedp/chromedp"
 	"github.com/stebennett/bin-notifier/pkg/dateutil"
+	"golang.org/x/net/html"
 )
 
-type WokinghamScraper struct{}
+// wokinghamURL is the council's collection day finder, a Drupal form.
+const wokinghamURL = "https://www.wokingham.gov.uk/rubbish-and-recycling/waste-collection/find-your-bin-collection-day"
+
+var wokinghamPostcodes = []string{"RG2", "RG4", "RG5", "RG6", "RG7", "RG10", "RG40", "RG41", "RG45"}
+
+func init() {
+	Register(Info{
+		Name:             "wokingham",
+		DisplayName:      "Wokingham Borough Council",
+		PostcodePrefixes: wokinghamPostcodes,
+		Fields:           addressFields,
+		New: func(opts Options) BinScraper {
+			return &WokinghamScraper{loc: opts.Location}
+		},
+	})
+}
+
+// WokinghamScraper submits the Wokingham collection day form over plain HTTP,
+// so it needs no browser.
+type WokinghamScraper struct {
+	loc *time.Location
+	// pageURL overrides wokinghamURL in tests.
+	pageURL string
+}
 
-func parseWokinghamCollection(heading string, dateText string) (BinTime, error) {
+func parseWokinghamCollection(heading string, dateText string, loc *time.Location) (BinTime, error) {
 	heading = strings.TrimSpace(heading)
 	dateText = strings.TrimSpace(dateText)
 
@@ -45,11 +69,24 @@ func parseWokinghamCollection(heading string, dateText string) (BinTime, error)
 
 	return BinTime{
 		Type:           binType,
-		CollectionTime: dateutil.AsTime(day, month, year),
+		CollectionTime: dateutil.AsTime(day, month, year, loc),
 	}, nil
 }
 
-func (s *WokinghamScraper) ScrapeBinTimes(postCode string, addressCode string) ([]BinTime, error) {
+// parseWokinghamCards parses the heading and date text of each collection card.
+func parseWokinghamCards(headings, dates []string, loc *time.Location) ([]BinTime, error) {
+	binTimes := make([]BinTime, 0, len(headings))
+	for i := range headings {
+		bt, err := parseWokinghamCollection(headings[i], dates[i], loc)
+		if err != nil {
+			return binTimes, err
+		}
+		binTimes = append(binTimes, bt)
+	}
+	return binTimes, nil
+}
+
+func (s *WokinghamScraper) ScrapeBinTimes(ctx context.Context, postCode string, addressCode string) ([]BinTime, error) {
 	if len(postCode) == 0 {
 		return []BinTime{}, errors.New("no postcode specified")
 	}
@@ -57,111 +94,53 @@ func (s *WokinghamScraper) ScrapeBinTimes(postCode string, addressCode string) (
 		return []BinTime{}, errors.New("no address specified")
 	}
 
-	log.Printf("creating temp user data dir")
-	dir, err := os.MkdirTemp("", "chromedp-wokingham")
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	pageURL := s.pageURL
+	if pageURL == "" {
+		pageURL = wokinghamURL
+	}
+	// The form keeps its state in the session, so cookies must be kept between steps.
+	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return []BinTime{}, err
 	}
-	defer os.RemoveAll(dir)
-
-	log.Printf("setting chrome defaults")
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.DisableGPU,
-		chromedp.UserDataDir(dir),
-		chromedp.Flag("headless", true),
-		chromedp.NoSandbox,
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
-
-	taskCtx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
-	defer cancel()
-
-	taskCtx, cancel = context.WithTimeout(taskCtx, 60*time.Second)
-	defer cancel()
-
-	log.Printf("navigating to wokingham waste collection page")
+	client := &http.Client{Jar: jar}
 
-	// Step 1: Navigate and accept cookies
-	err = chromedp.Run(taskCtx,
-		chromedp.Navigate("https://www.wokingham.gov.uk/rubbish-and-recycling/waste-collection/find-your-bin-collection-day"),
-		chromedp.WaitVisible(`#edit-postcode-search`, chromedp.ByQuery),
-	)
+	log.Printf("fetching wokingham waste collection page")
+	doc, err := fetchHTML(ctx, client, http.MethodGet, pageURL, nil)
 	if err != nil {
 		return []BinTime{}, err
 	}
 
-	// Accept cookies (ignore error if no banner)
-	chromedp.Run(taskCtx,
-		chromedp.Click(`.agree-button`, chromedp.ByQuery),
-		chromedp.Sleep(500*time.Millisecond),
-	)
-
-	// Step 2: Enter postcode and submit
 	log.Printf("entering postcode: %s", postCode)
-	err = chromedp.Run(taskCtx,
-		chromedp.SetValue(`#edit-postcode-search`, postCode, chromedp.ByQuery),
-		chromedp.Sleep(300*time.Millisecond),
-		chromedp.Click(`#edit-find-address`, chromedp.ByQuery),
-		chromedp.Sleep(3*time.Second),
-	)
+	doc, err = submitForm(ctx, client, pageURL, doc, "edit-find-address", map[string]string{"edit-postcode-search": postCode})
 	if err != nil {
 		return []BinTime{}, err
 	}
 
-	// Step 3: Select address and show collection dates
 	log.Printf("selecting address: %s", addressCode)
-	err = chromedp.Run(taskCtx,
-		chromedp.WaitVisible(`#edit-address-options`, chromedp.ByQuery),
-		chromedp.SetValue(`#edit-address-options`, addressCode, chromedp.ByQuery),
-		chromedp.Sleep(300*time.Millisecond),
-		chromedp.Click(`#edit-show-collection-dates`, chromedp.ByQuery),
-		chromedp.Sleep(5*time.Second),
-	)
+	doc, err = submitForm(ctx, client, pageURL, doc, "edit-show-collection-dates", map[string]string{"edit-address-options": addressCode})
 	if err != nil {
 		return []BinTime{}, err
 	}
 
-	// Step 4: Wait for results and extract card data
 	log.Printf("extracting collection dates")
-	var cardCount int
-	err = chromedp.Run(taskCtx,
-		chromedp.WaitVisible(`.cards-list`, chromedp.ByQuery),
-		chromedp.Evaluate(`document.querySelectorAll('.card--waste').length`, &cardCount),
-	)
-	if err != nil {
-		return []BinTime{}, err
-	}
-
-	if cardCount == 0 {
-		return []BinTime{}, errors.New("no collection cards found")
-	}
-
-	// Extract headings and dates from each card
 	var headings, dates []string
-	err = chromedp.Run(taskCtx,
-		chromedp.Evaluate(`Array.from(document.querySelectorAll('.card--waste h3')).map(h => h.textContent.trim())`, &headings),
-		chromedp.Evaluate(`Array.from(document.querySelectorAll('.card--waste .card__date')).map(d => d.textContent.trim())`, &dates),
-	)
-	if err != nil {
-		return []BinTime{}, err
+	for _, card := range findAll(doc, func(n *html.Node) bool { return hasClass(n, "card--waste") }) {
+		heading := findFirst(card, func(n *html.Node) bool { return n.Data == "h3" })
+		date := findFirst(card, func(n *html.Node) bool { return hasClass(n, "card__date") })
+		if heading == nil || date == nil {
+			return []BinTime{}, errors.New("collection card missing heading or date")
+		}
+		headings = append(headings, textContent(heading))
+		dates = append(dates, textContent(date))
 	}
-
-	if len(headings) != len(dates) {
-		return []BinTime{}, errors.New("mismatched headings and dates count")
+	if len(headings) == 0 {
+		return []BinTime{}, errors.New("no collection cards found")
 	}
 
 	log.Printf("found %d collection cards, parsing", len(headings))
-
-	binTimes := make([]BinTime, 0, len(headings))
-	for i := range headings {
-		bt, err := parseWokinghamCollection(headings[i], dates[i])
-		if err != nil {
-			return binTimes, err
-		}
-		binTimes = append(binTimes, bt)
-	}
-
-	return binTimes, nil
+	return parseWokinghamCards(headings, dates, s.loc)
 }
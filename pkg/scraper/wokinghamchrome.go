@@ -0,0 +1,123 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/stebennett/bin-notifier/pkg/browser"
+)
+
+func init() {
+	Register(Info{
+		Name:             "wokingham-chrome",
+		DisplayName:      "Wokingham Borough Council (headless Chrome)",
+		PostcodePrefixes: wokinghamPostcodes,
+		Fields:           addressFields,
+		New: func(opts Options) BinScraper {
+			return &WokinghamChromeScraper{loc: opts.Location, browser: opts.Browser}
+		},
+	})
+}
+
+// WokinghamChromeScraper drives the Wokingham collection day form in headless
+// Chrome. It is a fallback for WokinghamScraper should the form stop working
+// without JavaScript.
+type WokinghamChromeScraper struct {
+	loc     *time.Location
+	browser *browser.Pool
+}
+
+func (s *WokinghamChromeScraper) ScrapeBinTimes(ctx context.Context, postCode string, addressCode string) ([]BinTime, error) {
+	if len(postCode) == 0 {
+		return []BinTime{}, errors.New("no postcode specified")
+	}
+	if len(addressCode) == 0 {
+		return []BinTime{}, errors.New("no address specified")
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	taskCtx, release, err := openTab(ctx, s.browser)
+	if err != nil {
+		return []BinTime{}, err
+	}
+	defer release()
+
+	log.Printf("navigating to wokingham waste collection page")
+
+	// Step 1: Navigate and accept cookies
+	err = chromedp.Run(taskCtx,
+		chromedp.Navigate(wokinghamURL),
+		chromedp.WaitVisible(`#edit-postcode-search`, chromedp.ByQuery),
+	)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	// Accept cookies (ignore error if no banner)
+	chromedp.Run(taskCtx,
+		chromedp.Click(`.agree-button`, chromedp.ByQuery),
+		chromedp.Sleep(500*time.Millisecond),
+	)
+
+	// Step 2: Enter postcode and submit
+	log.Printf("entering postcode: %s", postCode)
+	err = chromedp.Run(taskCtx,
+		chromedp.SetValue(`#edit-postcode-search`, postCode, chromedp.ByQuery),
+		chromedp.Sleep(300*time.Millisecond),
+		chromedp.Click(`#edit-find-address`, chromedp.ByQuery),
+		chromedp.Sleep(3*time.Second),
+	)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	// Step 3: Select address and show collection dates
+	log.Printf("selecting address: %s", addressCode)
+	err = chromedp.Run(taskCtx,
+		chromedp.WaitVisible(`#edit-address-options`, chromedp.ByQuery),
+		chromedp.SetValue(`#edit-address-options`, addressCode, chromedp.ByQuery),
+		chromedp.Sleep(300*time.Millisecond),
+		chromedp.Click(`#edit-show-collection-dates`, chromedp.ByQuery),
+		chromedp.Sleep(5*time.Second),
+	)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	// Step 4: Wait for results and extract card data
+	log.Printf("extracting collection dates")
+	var cardCount int
+	err = chromedp.Run(taskCtx,
+		chromedp.WaitVisible(`.cards-list`, chromedp.ByQuery),
+		chromedp.Evaluate(`document.querySelectorAll('.card--waste').length`, &cardCount),
+	)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	if cardCount == 0 {
+		return []BinTime{}, errors.New("no collection cards found")
+	}
+
+	// Extract headings and dates from each card
+	var headings, dates []string
+	err = chromedp.Run(taskCtx,
+		chromedp.Evaluate(`Array.from(document.querySelectorAll('.card--waste h3')).map(h => h.textContent.trim())`, &headings),
+		chromedp.Evaluate(`Array.from(document.querySelectorAll('.card--waste .card__date')).map(d => d.textContent.trim())`, &dates),
+	)
+	if err != nil {
+		return []BinTime{}, err
+	}
+
+	if len(headings) != len(dates) {
+		return []BinTime{}, errors.New("mismatched headings and dates count")
+	}
+
+	log.Printf("found %d collection cards, parsing", len(headings))
+	return parseWokinghamCards(headings, dates, s.loc)
+}
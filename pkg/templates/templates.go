@@ -0,0 +1,131 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sources of a message: collections found on the council website, or an
+// expected collection day from the configured schedule with nothing scheduled.
+const (
+	SourceScraped  = "scraped"
+	SourceExpected = "expected"
+)
+
+// Built-in templates used when no override is configured.
+const (
+	DefaultCollection      = `{{.Label}}: {{if eq .When "tomorrow"}}Tomorrows bin collections are{{else}}Bin collections {{.When}} ({{.Weekday}}) are{{end}}: {{join .Types ", "}}`
+	DefaultScheduleWarning = `{{.Label}}: Expected {{join .Types ", "}} collection {{.When}} ({{.Weekday}}) but none scheduled.`
+	DefaultBringIn         = `{{.Label}}: Bring your bins in ({{join .Types ", "}})`
+)
+
+// Data is the data available to a message template.
+type Data struct {
+	Label    string
+	PostCode string
+	Date     time.Time
+	Weekday  string
+	When     string
+	Types    []string
+	Source   string
+}
+
+// BinCategory is a kind of bin: its emoji, and the emoji shortcode used for ntfy
+// tags and Slack.
+type BinCategory struct {
+	Emoji string
+	Tag   string
+}
+
+var (
+	recycling    = BinCategory{Emoji: "♻️", Tag: "recycle"}
+	gardenWaste  = BinCategory{Emoji: "🌳", Tag: "deciduous_tree"}
+	foodWaste    = BinCategory{Emoji: "🍎", Tag: "green_apple"}
+	glass        = BinCategory{Emoji: "🍾", Tag: "wine_glass"}
+	paper        = BinCategory{Emoji: "📰", Tag: "newspaper"}
+	generalWaste = BinCategory{Emoji: "🗑️", Tag: "wastebasket"}
+)
+
+// binCategories maps keywords found in bin type names to their category.
+var binCategories = []struct {
+	keyword  string
+	category BinCategory
+}{
+	{"recycl", recycling},
+	{"garden", gardenWaste},
+	{"food", foodWaste},
+	{"glass", glass},
+	{"paper", paper},
+	{"general", generalWaste},
+	{"household", generalWaste},
+	{"refuse", generalWaste},
+}
+
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"title": Title,
+	"emoji": Emoji,
+}
+
+// sample is rendered by Validate so that references to unknown fields fail
+// when the config is loaded rather than when a message is sent.
+var sample = Data{
+	Label:    "Home",
+	PostCode: "RG12 1AB",
+	Date:     time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+	Weekday:  "Friday",
+	When:     "tomorrow",
+	Types:    []string{"General Waste", "Recycling"},
+	Source:   SourceScraped,
+}
+
+// Category returns the category of a bin type, defaulting to general waste.
+func Category(binType string) BinCategory {
+	lower := strings.ToLower(binType)
+	for _, c := range binCategories {
+		if strings.Contains(lower, c.keyword) {
+			return c.category
+		}
+	}
+	return generalWaste
+}
+
+// Emoji returns an emoji for a bin type, defaulting to a wastebasket.
+func Emoji(binType string) string {
+	return Category(binType).Emoji
+}
+
+// Title upper-cases the first letter of each word and lower-cases the rest.
+func Title(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToTitle(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Validate parses text and renders it against sample data, returning any error.
+func Validate(text string) error {
+	_, err := Render(text, sample)
+	return err
+}
+
+// Render executes the template text with data. Leading and trailing whitespace
+// is trimmed from the result so YAML block scalars can be used in config.
+func Render(text string, data Data) (string, error) {
+	tmpl, err := template.New("message").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
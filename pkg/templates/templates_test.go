@@ -0,0 +1,125 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData() Data {
+	return Data{
+		Label:   "Home",
+		Date:    time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+		Weekday: "Friday",
+		When:    "tomorrow",
+		Types:   []string{"Recycling", "Garden Waste"},
+		Source:  SourceScraped,
+	}
+}
+
+func TestRender_Defaults(t *testing.T) {
+	text, err := Render(DefaultCollection, testData())
+	require.NoError(t, err)
+	assert.Equal(t, "Home: Tomorrows bin collections are: Recycling, Garden Waste", text)
+
+	data := testData()
+	data.Source = SourceExpected
+	text, err = Render(DefaultScheduleWarning, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Home: Expected Recycling, Garden Waste collection tomorrow (Friday) but none scheduled.", text)
+
+	data = testData()
+	data.When = "in 2 days"
+	text, err = Render(DefaultCollection, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Home: Bin collections in 2 days (Friday) are: Recycling, Garden Waste", text)
+
+	data.Source = SourceExpected
+	text, err = Render(DefaultScheduleWarning, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Home: Expected Recycling, Garden Waste collection in 2 days (Friday) but none scheduled.", text)
+
+	text, err = Render(DefaultBringIn, testData())
+	require.NoError(t, err)
+	assert.Equal(t, "Home: Bring your bins in (Recycling, Garden Waste)", text)
+}
+
+func TestRender_HelperFuncs(t *testing.T) {
+	text, err := Render(`{{title .Label}} {{.Date.Format "2 Jan"}}:{{range .Types}} {{emoji .}} {{title .}}{{end}}`, Data{
+		Label: "mum's house",
+		Date:  time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+		Types: []string{"FOOD waste", "glass"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mum's House 16 Jan: 🍎 Food Waste 🍾 Glass", text)
+}
+
+func TestRender_BranchesOnSource(t *testing.T) {
+	tmpl := `{{if eq .Source "expected"}}Check the council site{{else}}Bins out{{end}}`
+
+	data := testData()
+	text, err := Render(tmpl, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Bins out", text)
+
+	data.Source = SourceExpected
+	text, err = Render(tmpl, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Check the council site", text)
+}
+
+func TestRender_TrimsWhitespace(t *testing.T) {
+	text, err := Render("\n  {{.Label}}\n", testData())
+
+	require.NoError(t, err)
+	assert.Equal(t, "Home", text)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"default collection", DefaultCollection, false},
+		{"default schedule warning", DefaultScheduleWarning, false},
+		{"default bring in", DefaultBringIn, false},
+		{"syntax error", `{{.Label`, true},
+		{"unknown field", `{{.Lable}}`, true},
+		{"unknown func", `{{upper .Label}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmoji(t *testing.T) {
+	assert.Equal(t, "♻️", Emoji("Recycling"))
+	assert.Equal(t, "🌳", Emoji("Garden Waste"))
+	assert.Equal(t, "🍎", Emoji("Food Waste"))
+	assert.Equal(t, "🗑️", Emoji("General Waste"))
+	assert.Equal(t, "🗑️", Emoji("Bulky Items"))
+}
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, BinCategory{Emoji: "🍾", Tag: "wine_glass"}, Category("Glass Box"))
+	assert.Equal(t, BinCategory{Emoji: "🗑️", Tag: "wastebasket"}, Category("Refuse"))
+	assert.Equal(t, BinCategory{Emoji: "🗑️", Tag: "wastebasket"}, Category("Bulky Items"))
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "General Waste", Title("general waste"))
+	assert.Equal(t, "Garden Waste", Title("GARDEN  WASTE"))
+	assert.Equal(t, "", Title(""))
+}